@@ -0,0 +1,79 @@
+package main
+
+import (
+	"hash"
+	"hash/fnv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// quietTicksBeforeBackoff is how many unchanged ticks -adaptive waits
+// before stretching the interval.
+const quietTicksBeforeBackoff = 3
+
+// adaptiveInterval stretches the watch interval while nothing changes and
+// snaps back to the base interval as soon as something does.
+type adaptiveInterval struct {
+	base       time.Duration
+	max        time.Duration
+	current    time.Duration
+	quietTicks int
+}
+
+func newAdaptiveInterval(base, max time.Duration) *adaptiveInterval {
+	if max < base {
+		max = base
+	}
+	return &adaptiveInterval{base: base, max: max, current: base}
+}
+
+// next returns the interval to sleep after a tick.
+func (a *adaptiveInterval) next(changed bool) time.Duration {
+	if changed {
+		a.quietTicks = 0
+		a.current = a.base
+		return a.current
+	}
+
+	a.quietTicks++
+	if a.quietTicks >= quietTicksBeforeBackoff {
+		a.current *= 2
+		if a.current > a.max {
+			a.current = a.max
+		}
+	}
+	return a.current
+}
+
+// changeTracker fingerprints every list fetched during a tick by object
+// identity and resourceVersion, so ticks can be compared cheaply.
+type changeTracker struct {
+	tick hash.Hash64
+	last uint64
+	seen bool
+}
+
+func newChangeTracker() *changeTracker {
+	return &changeTracker{tick: fnv.New64a()}
+}
+
+func (c *changeTracker) add(list runtime.Object) {
+	meta.EachListItem(list, func(obj runtime.Object) error {
+		if accessor, err := meta.Accessor(obj); err == nil {
+			c.tick.Write([]byte(string(accessor.GetUID()) + "/" + accessor.GetResourceVersion() + "\n"))
+		}
+		return nil
+	})
+}
+
+// commit ends the tick and reports whether it differed from the previous
+// one. The first tick always counts as a change.
+func (c *changeTracker) commit() bool {
+	sum := c.tick.Sum64()
+	c.tick.Reset()
+	changed := !c.seen || sum != c.last
+	c.last, c.seen = sum, true
+	return changed
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+)
+
+// auditLog appends one JSON line per observed STATUS change to
+// -audit-file, whatever the on-screen format, so an incident timeline can
+// be rebuilt afterwards. Objects seen for the first time are recorded with
+// an empty oldStatus and deleted ones with an empty newStatus.
+type auditLog struct {
+	file   *os.File
+	w      *bufio.Writer
+	states map[string]map[string]string // per kind, and cluster for TYPE@CLUSTER
+}
+
+// AuditRecord is one line of -audit-file.
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	Kind      string    `json:"kind"`
+	Cluster   string    `json:"cluster,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	OldStatus string    `json:"oldStatus"`
+	NewStatus string    `json:"newStatus"`
+}
+
+func openAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLog{file: file, w: bufio.NewWriter(file), states: map[string]map[string]string{}}, nil
+}
+
+// recordAudit buffers the records for this tick's rows; flush writes them
+// out. Kinds without a STATUS column are ignored.
+func recordAudit[T any](a *auditLog, kind, cluster string, columns []column[T], rows []T) error {
+	statuses, ok := rowStatuses(columns, rows)
+	if !ok {
+		return nil
+	}
+	section := atCluster(kind, cluster)
+	if a.states[section] == nil {
+		a.states[section] = map[string]string{}
+	}
+	states := a.states[section]
+
+	now := time.Now().UTC()
+	encoder := json.NewEncoder(a.w)
+	write := func(key, oldStatus, newStatus string) error {
+		record := AuditRecord{Time: now, Kind: strings.TrimSuffix(kind, "s"), Cluster: cluster, Name: key, OldStatus: oldStatus, NewStatus: newStatus}
+		if namespace, name, ok := strings.Cut(key, "/"); ok {
+			record.Namespace, record.Name = namespace, name
+		}
+		return encoder.Encode(record)
+	}
+
+	seen := map[string]bool{}
+	for _, row := range statuses {
+		seen[row.key] = true
+		if previous, ok := states[row.key]; ok && previous == row.status {
+			continue
+		}
+		if err := write(row.key, states[row.key], row.status); err != nil {
+			return err
+		}
+		states[row.key] = row.status
+	}
+	for key, previous := range states {
+		if !seen[key] {
+			if err := write(key, previous, ""); err != nil {
+				return err
+			}
+			delete(states, key)
+		}
+	}
+	return nil
+}
+
+func (a *auditLog) flush() error {
+	return a.w.Flush()
+}
+
+func (a *auditLog) close() error {
+	if err := a.w.Flush(); err != nil {
+		a.file.Close()
+		return err
+	}
+	return a.file.Close()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// snapshot is one tick's rendered rows keyed by object. Rows are compared
+// by their columns' stable values, so cells that only count time, such as
+// AGE or "Running (5m)", are not changes; cells are what is shown.
+type snapshot struct {
+	headers []string
+	keys    []string
+	cells   map[string][]string
+	stable  map[string][]string
+}
+
+func newSnapshot[T any](columns []column[T], rows []T) *snapshot {
+	snap := &snapshot{cells: map[string][]string{}, stable: map[string][]string{}}
+	for _, col := range columns {
+		snap.headers = append(snap.headers, col.header)
+	}
+
+	keys := rowKeys{}
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		stable := make([]string, len(columns))
+		for i, col := range columns {
+			cells[i] = col.value(row)
+			stable[i] = cells[i]
+			if col.stable != nil {
+				stable[i] = col.stable(row)
+			}
+		}
+		key := keys.next(rowKey(row, cells))
+		snap.keys = append(snap.keys, key)
+		snap.cells[key] = cells
+		snap.stable[key] = stable
+	}
+	return snap
+}
+
+// rowKey identifies a row across ticks by namespace/name when the row
+// carries metadata, otherwise by its first cell.
+func rowKey[T any](row T, cells []string) string {
+	if m, ok := any(row).(interface{ objectMeta() metav1.Object }); ok && m.objectMeta() != nil {
+		if ns := m.objectMeta().GetNamespace(); ns != "" {
+			return ns + "/" + m.objectMeta().GetName()
+		}
+		return m.objectMeta().GetName()
+	}
+	if len(cells) == 0 {
+		return ""
+	}
+	return cells[0]
+}
+
+// rowKeys tells apart the rows of one object, such as a pod's containers,
+// which share its rowKey: the second and later rows get #2, #3 and so on.
+type rowKeys map[string]int
+
+func (k rowKeys) next(key string) string {
+	if k[key]++; k[key] > 1 {
+		return key + "#" + strconv.Itoa(k[key])
+	}
+	return key
+}
+
+func (s *snapshot) equal(other *snapshot) bool {
+	if len(s.keys) != len(other.keys) {
+		return false
+	}
+	for _, key := range s.keys {
+		stable, ok := other.stable[key]
+		if !ok || strings.Join(stable, "\x00") != strings.Join(s.stable[key], "\x00") {
+			return false
+		}
+	}
+	return true
+}
+
+// changeFilter remembers the last printed snapshot of each kind for
+// -watch-on-change-only. It outlives the per-tick printer.
+type changeFilter struct {
+	last map[string]*snapshot
+}
+
+// swap stores current and returns the previous snapshot of kind, nil on
+// the first tick.
+func (c *changeFilter) swap(kind string, current *snapshot) *snapshot {
+	if c.last == nil {
+		c.last = map[string]*snapshot{}
+	}
+	previous := c.last[kind]
+	c.last[kind] = current
+	return previous
+}
+
+// printDiff writes added (+), removed (-) and changed (~) rows between two
+// snapshots, or nothing at all when they are equal.
+func printDiff(w io.Writer, kind string, previous, current *snapshot) {
+	if previous.equal(current) {
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s %s changed:\n", time.Now().Format("15:04:05"), kind)
+	for _, key := range current.keys {
+		old, existed := previous.cells[key]
+		if !existed {
+			fmt.Fprintf(w, "  + %s  %s\n", key, strings.Join(current.cells[key][1:], "  "))
+			continue
+		}
+
+		var changes []string
+		for i, cell := range current.cells[key] {
+			if i < len(old) && previous.stable[key][i] != current.stable[key][i] {
+				changes = append(changes, fmt.Sprintf("%s: %s → %s", current.headers[i], old[i], cell))
+			}
+		}
+		if len(changes) > 0 {
+			fmt.Fprintf(w, "  ~ %s  %s\n", key, strings.Join(changes, ", "))
+		}
+	}
+	for _, key := range previous.keys {
+		if _, ok := current.cells[key]; !ok {
+			fmt.Fprintf(w, "  - %s\n", key)
+		}
+	}
+}
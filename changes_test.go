@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSnapshotKeepsEachContainerOfAPod(t *testing.T) {
+	meta := rowMeta{&metav1.ObjectMeta{Namespace: "default", Name: "web"}}
+	tick := func(sidecarState string) *snapshot {
+		return newSnapshot(containerColumns, []ContainerRow{
+			{rowMeta: meta, Namespace: "default", Pod: "web", Container: "app", Type: "container", State: "Running", Ready: true},
+			{rowMeta: meta, Namespace: "default", Pod: "web", Container: "proxy", Type: "container", State: sidecarState, Ready: true},
+		})
+	}
+
+	previous, current := tick("Running"), tick("Waiting: CrashLoopBackOff")
+	if len(current.cells) != 2 {
+		t.Fatalf("snapshot has %d rows, want one per container", len(current.cells))
+	}
+	var out bytes.Buffer
+	printDiff(&out, "containers", previous, current)
+	if !strings.Contains(out.String(), "STATE: Running → Waiting: CrashLoopBackOff") {
+		t.Errorf("change to the second container not reported:\n%s", out.String())
+	}
+}
+
+func TestSnapshotIgnoresStatusDetail(t *testing.T) {
+	tick := func(status string) *snapshot {
+		return newSnapshot(podColumns, []PodRow{{
+			rowMeta: rowMeta{&metav1.ObjectMeta{Namespace: "default", Name: "web"}},
+			Name:    "web",
+			Status:  status,
+			Ready:   "0/1",
+		}})
+	}
+
+	var out bytes.Buffer
+	printDiff(&out, "pods", tick("Terminating (3h)"), tick("Terminating (4h)"))
+	if out.Len() != 0 {
+		t.Errorf("the age of a Terminating pod reported as a change:\n%s", out.String())
+	}
+	printDiff(&out, "pods", tick("Running"), tick("Terminating (1s)"))
+	if !strings.Contains(out.String(), "STATUS: Running → Terminating") {
+		t.Errorf("status change not reported:\n%s", out.String())
+	}
+}
+
+func TestSnapshotIgnoresTimePassing(t *testing.T) {
+	meta := rowMeta{&metav1.ObjectMeta{Namespace: "default", Name: "web"}}
+	finished := time.Now().Add(-time.Hour)
+	tick := func(state string) *snapshot {
+		return newSnapshot(containerColumns, []ContainerRow{{
+			rowMeta: meta, Pod: "web", Container: "app", State: state,
+			LastTermination: &Termination{Reason: "Error", ExitCode: 1, FinishedAt: finished},
+		}})
+	}
+
+	var out bytes.Buffer
+	printDiff(&out, "containers", tick("Running (4m)"), tick("Running (5m)"))
+	if out.Len() != 0 {
+		t.Errorf("time passing reported as a change:\n%s", out.String())
+	}
+	printDiff(&out, "containers", tick("Running (5m)"), tick("Waiting: CrashLoopBackOff"))
+	if !strings.Contains(out.String(), "STATE: Running (5m) → Waiting: CrashLoopBackOff") {
+		t.Errorf("state change not reported:\n%s", out.String())
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// churnCounter counts the adds, updates and deletes seen between watch
+// ticks, by comparing each list's resourceVersions with the previous tick's,
+// for the watch header.
+type churnCounter struct {
+	started                 time.Time
+	seen                    map[string]map[types.UID]string // per list scope
+	counted                 map[types.UID]string            // the last version of each object counted
+	added, updated, deleted int
+}
+
+func newChurnCounter() *churnCounter {
+	return &churnCounter{started: time.Now(), seen: map[string]map[types.UID]string{}, counted: map[types.UID]string{}}
+}
+
+// observe compares list with the previous list of the same scope: kind,
+// the list type and the cluster it came from, namespace and selectors, as
+// one tick may list a type several ways, such as the selected pods and
+// then every pod for quota use. An object in several of those lists is
+// counted once. The first list of a scope is only the baseline.
+func (c *churnCounter) observe(kind, namespace string, opts metav1.ListOptions, list runtime.Object) {
+	current := map[types.UID]string{}
+	meta.EachListItem(list, func(obj runtime.Object) error {
+		if accessor, err := meta.Accessor(obj); err == nil {
+			current[accessor.GetUID()] = accessor.GetResourceVersion()
+		}
+		return nil
+	})
+
+	scope := strings.Join([]string{kind, namespace, opts.LabelSelector, opts.FieldSelector}, " ")
+	previous, ok := c.seen[scope]
+	c.seen[scope] = current
+	for uid, version := range current {
+		last, known := c.counted[uid]
+		c.counted[uid] = version
+		if !ok || previous[uid] == version || (known && last == version) {
+			continue
+		}
+		if known {
+			c.updated++
+		} else {
+			c.added++
+		}
+	}
+	if !ok {
+		return
+	}
+	for uid := range previous {
+		if _, ok := current[uid]; ok {
+			continue
+		}
+		// already counted through another list
+		if _, known := c.counted[uid]; known {
+			c.deleted++
+			delete(c.counted, uid)
+		}
+	}
+}
+
+func (c *churnCounter) header() string {
+	return fmt.Sprintf("Session %s: %d added, %d updated, %d deleted",
+		time.Since(c.started).Round(time.Second), c.added, c.updated, c.deleted)
+}
+
+// eraseLines clears the rest of every line it writes, so a tick can be
+// drawn over the previous one without clearing the whole screen first.
+// Lines end in \r\n as the terminal may be in raw mode for the keyboard.
+type eraseLines struct {
+	w io.Writer
+}
+
+func (e eraseLines) Write(p []byte) (int, error) {
+	if _, err := e.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\033[K\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
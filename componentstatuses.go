@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// componentStatusesRemoved is shown where the cluster no longer serves
+// ComponentStatuses, or serves an empty list as many managed clusters do.
+const componentStatusesRemoved = "componentstatuses are deprecated and this cluster does not report them; check the control-plane pods instead with -resource pods -namespace kube-system"
+
+var componentStatusColumns = []column[ComponentStatusRow]{
+	{"NAME", 30, func(r ComponentStatusRow) string { return r.Name }, nil},
+	{"STATUS", 12, func(r ComponentStatusRow) string { return r.Status }, nil},
+	{"MESSAGE", 0, func(r ComponentStatusRow) string { return r.Message }, nil},
+}
+
+// listComponentStatuses lists the control-plane components' health, on
+// clusters that still report it.
+func listComponentStatuses(ctx context.Context, p *printer, src *source, opts metav1.ListOptions) {
+	statuses, err := fetch(src, &corev1.ComponentStatusList{}, "", opts, func() (*corev1.ComponentStatusList, error) {
+		return src.clientset.CoreV1().ComponentStatuses().List(ctx, opts)
+	})
+	if apierrors.IsNotFound(err) {
+		handleError(fmt.Errorf("%s", componentStatusesRemoved))
+		return
+	}
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []ComponentStatusRow
+	for _, status := range statuses.Items {
+		rows = append(rows, newComponentStatusRow(status))
+	}
+	printRows(p, "componentstatuses", statuses, componentStatusColumns, rows)
+	if len(rows) == 0 && p.format == "table" {
+		fmt.Fprintf(p.w, "Note: %s\n", componentStatusesRemoved)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the optional -config file, YAML such as:
+//
+//	fields:
+//	  pods: [name, status, node, age]
+//	  deployments: [name, ready, age]
+//	clusters:
+//	  prod: {kubeconfig: ~/.kube/prod, context: admin@prod}
+type Config struct {
+	// Fields are the columns shown per resource type when -fields is not
+	// given.
+	Fields map[string][]string `json:"fields"`
+	// Clusters are what the CLUSTER of -resource TYPE@CLUSTER refers to.
+	Clusters map[string]ClusterConfig `json:"clusters"`
+}
+
+// ClusterConfig picks another cluster than the main one. An empty
+// Kubeconfig is found as for the main cluster, and an empty Context is the
+// kubeconfig's current context.
+type ClusterConfig struct {
+	Kubeconfig string `json:"kubeconfig"`
+	Context    string `json:"context"`
+}
+
+// defaultConfigPath is k8s-monitor/config.yaml in the user's config
+// directory, e.g. ~/.config/k8s-monitor/config.yaml.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "k8s-monitor", "config.yaml")
+}
+
+// clientConfig loads the cluster's kubeconfig the way the main one is
+// loaded.
+func (cluster ClusterConfig) clientConfig() clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = cluster.Kubeconfig
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(cluster.Kubeconfig, "~/") {
+		rules.ExplicitPath = filepath.Join(home, cluster.Kubeconfig[2:])
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: cluster.Context})
+}
+
+// loadConfig reads the config file at path. A missing file is only an
+// error when the path was given explicitly. Resource aliases are resolved
+// so "pod" and "pods" configure the same columns.
+func loadConfig(path string, explicit bool) (*Config, error) {
+	config := &Config{}
+	if path == "" {
+		return config, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	fields := map[string][]string{}
+	for resource, columns := range config.Fields {
+		if info, ok := lookupResource(resource); ok {
+			resource = info.name
+		}
+		fields[resource] = columns
+	}
+	config.Fields = fields
+	return config, nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var containerColumns = []column[ContainerRow]{
+	{"POD", 40, func(r ContainerRow) string { return r.Pod }, nil},
+	{"CONTAINER", 25, func(r ContainerRow) string { return r.Container }, nil},
+	{"TYPE", 22, func(r ContainerRow) string {
+		if r.Target != "" {
+			return r.Type + " (" + r.Target + ")"
+		}
+		return r.Type
+	}, nil},
+	{"INJECTED-BY", 12, func(r ContainerRow) string { return orNone(r.InjectedBy) }, nil},
+	{"STATE", 34, func(r ContainerRow) string { return r.State }, func(r ContainerRow) string {
+		// how long it has been running or starting is not a change
+		if strings.HasPrefix(r.State, "Running (") || strings.HasPrefix(r.State, "Starting (") {
+			return withoutDetail(r.State)
+		}
+		return r.State
+	}},
+	{"READY", 6, func(r ContainerRow) string { return strconv.FormatBool(r.Ready) }, nil},
+	{"RESTARTS", 10, func(r ContainerRow) string { return strconv.Itoa(int(r.Restarts)) }, nil},
+	{"LAST-RESTART", 36, func(r ContainerRow) string {
+		t := r.LastTermination
+		if t == nil {
+			return "<none>"
+		}
+		return fmt.Sprintf("%s (exit %d) %s ago", orNone(t.Reason), t.ExitCode, formatAge(t.FinishedAt))
+	}, func(r ContainerRow) string {
+		if t := r.LastTermination; t != nil {
+			return fmt.Sprintf("%s (exit %d) at %s", t.Reason, t.ExitCode, t.FinishedAt.Format(time.RFC3339))
+		}
+		return ""
+	}},
+	{"IMAGE", 0, func(r ContainerRow) string { return r.Image }, nil},
+}
+
+// listContainers prints one line per container of every pod, init and
+// ephemeral containers included. Ephemeral containers are the ones
+// `kubectl debug` attaches, so they show active debug sessions.
+func listContainers(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	pods, err := fetch(src, &corev1.PodList{}, namespace, opts, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []ContainerRow
+	for _, pod := range pods.Items {
+		rows = append(rows, buildContainerRows(pod)...)
+	}
+	printRows(p, "containers", nil, containerColumns, rows)
+
+	// readiness as the application sees it, leaving out the mesh's
+	// proxies
+	if p.format == "table" && p.onChange == nil {
+		app, appReady, injected := 0, 0, 0
+		for _, row := range rows {
+			switch {
+			case row.InjectedBy != "":
+				injected++
+			case row.Type == "regular":
+				app++
+				if row.Ready {
+					appReady++
+				}
+			}
+		}
+		if injected > 0 {
+			fmt.Fprintf(p.w, "Injected sidecars: %d; application containers ready: %d/%d\n", injected, appReady, app)
+		}
+	}
+}
+
+func buildContainerRows(pod corev1.Pod) []ContainerRow {
+	statuses := map[string]corev1.ContainerStatus{}
+	for _, list := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, pod.Status.EphemeralContainerStatuses} {
+		for _, status := range list {
+			statuses[status.Name] = status
+		}
+	}
+	injected := injectedContainers(pod)
+	startupProbes := map[string]corev1.Container{}
+	for _, c := range pod.Spec.Containers {
+		if c.StartupProbe != nil {
+			startupProbes[c.Name] = c
+		}
+	}
+	row := func(name, image, kind string) ContainerRow {
+		status, ok := statuses[name]
+		state := containerState(status, ok)
+		if probe, ok := startupProbes[name]; ok && awaitingStartupProbe(probe, status) {
+			state = "Starting (startup probe " + describeProbeWait(probe.StartupProbe, status) + ")"
+		}
+		var last *Termination
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			last = &Termination{terminated.Reason, terminated.ExitCode, terminated.FinishedAt.Time}
+		}
+		return ContainerRow{
+			rowMeta:         rowMeta{&pod.ObjectMeta},
+			Namespace:       pod.Namespace,
+			Pod:             pod.Name,
+			Container:       name,
+			Type:            kind,
+			InjectedBy:      injected[name],
+			Image:           image,
+			State:           state,
+			Ready:           status.Ready,
+			Restarts:        status.RestartCount,
+			LastTermination: last,
+		}
+	}
+
+	var rows []ContainerRow
+	for _, c := range pod.Spec.InitContainers {
+		rows = append(rows, row(c.Name, c.Image, "init"))
+	}
+	for _, c := range pod.Spec.Containers {
+		rows = append(rows, row(c.Name, c.Image, "regular"))
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		r := row(c.Name, c.Image, "ephemeral")
+		r.Target = c.TargetContainerName
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+func containerState(status corev1.ContainerStatus, known bool) string {
+	switch {
+	case !known:
+		return "<unknown>"
+	case status.State.Running != nil:
+		return "Running (" + formatAge(status.State.Running.StartedAt.Time) + ")"
+	case status.State.Waiting != nil:
+		return "Waiting: " + orNone(status.State.Waiting.Reason)
+	case status.State.Terminated != nil:
+		return fmt.Sprintf("Terminated: %s (exit %d)", status.State.Terminated.Reason, status.State.Terminated.ExitCode)
+	}
+	return "<unknown>"
+}
+
+// describeProbeWait says how long a container has been waiting on its
+// startup probe against the longest the kubelet will wait before
+// restarting it.
+func describeProbeWait(probe *corev1.Probe, status corev1.ContainerStatus) string {
+	failureThreshold, period := probe.FailureThreshold, probe.PeriodSeconds
+	if failureThreshold == 0 {
+		failureThreshold = 3
+	}
+	if period == 0 {
+		period = 10
+	}
+	limit := time.Duration(probe.InitialDelaySeconds+failureThreshold*period) * time.Second
+	return fmt.Sprintf("%s/%s", formatAge(status.State.Running.StartedAt.Time), limit)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+)
+
+// errCredentialsExpired replaces the API server's 401s once reloading the
+// kubeconfig did not help.
+var errCredentialsExpired = errors.New("credentials expired, please re-authenticate (e.g. log in again to refresh the kubeconfig); the watch resumes once they work")
+
+// credentialReloader lets a watch outlive short-lived credentials, such as
+// OIDC tokens, by reading the kubeconfig again after a 401. A reloaded
+// bearer token replaces the one the clients were built with on every
+// later request. Exec plugins and auth providers refresh themselves on a
+// 401, so for those the retry alone picks up the new credentials.
+type credentialReloader struct {
+	load func() (*rest.Config, error)
+
+	mu    sync.Mutex
+	token string // the reloaded bearer token, "" to keep the original
+}
+
+// wrap is installed with rest.Config.Wrap, below client-go's own
+// authentication, so the reloaded token wins.
+func (c *credentialReloader) wrap(next http.RoundTripper) http.RoundTripper {
+	return &reloadedTokenTransport{next: next, creds: c}
+}
+
+// reload reads the kubeconfig again and keeps its bearer token.
+func (c *credentialReloader) reload() error {
+	config, err := c.load()
+	if err != nil {
+		return err
+	}
+	token := config.BearerToken
+	if config.BearerTokenFile != "" {
+		data, err := os.ReadFile(config.BearerTokenFile)
+		if err != nil {
+			return err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	return nil
+}
+
+func (c *credentialReloader) bearerToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+type reloadedTokenTransport struct {
+	next  http.RoundTripper
+	creds *credentialReloader
+}
+
+func (t *reloadedTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if token := t.creds.bearerToken(); token != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// reauthenticate reloads the credentials after call was rejected as
+// unauthorized and tries it once more. The first time that fails too it
+// says so on r.w; until the credentials work again every rejected call
+// returns errCredentialsExpired rather than the raw 401.
+func reauthenticate[L any](r *reconnector, creds *credentialReloader, call func() (L, error)) (L, error) {
+	var result L
+	err := creds.reload()
+	if err == nil {
+		result, err = call()
+		if err == nil {
+			if r.setStatus(stateConnected) == stateExpired {
+				fmt.Fprintln(r.w, "Credentials renewed, the watch has resumed")
+			}
+			return result, nil
+		}
+		if !apierrors.IsUnauthorized(err) {
+			return result, err
+		}
+	}
+
+	if r.setStatus(stateExpired) != stateExpired {
+		fmt.Fprintf(r.w, "The API server rejected the credentials and reloading the kubeconfig did not help (%v)\n", err)
+	}
+	return result, errCredentialsExpired
+}
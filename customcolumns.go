@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// customColumn is one HEADER:JSONPATH pair of -o custom-columns.
+type customColumn struct {
+	header string
+	path   *jsonpath.JSONPath
+}
+
+// parseCustomColumns parses kubectl's inline syntax:
+// NAME:.metadata.name,NODE:.spec.nodeName
+func parseCustomColumns(spec string) ([]customColumn, error) {
+	var columns []customColumn
+	for _, part := range strings.Split(spec, ",") {
+		header, path, ok := strings.Cut(part, ":")
+		if !ok || header == "" || path == "" {
+			return nil, fmt.Errorf("invalid custom-columns spec %q, expected HEADER:JSONPATH", part)
+		}
+		column, err := newCustomColumn(header, path)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// readCustomColumnsFile parses kubectl's file syntax: a line of headers
+// followed by a line of JSONPath expressions, both whitespace-separated.
+func readCustomColumnsFile(path string) ([]customColumn, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines [][]string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) != 2 || len(lines[0]) != len(lines[1]) {
+		return nil, fmt.Errorf("%s: expected a line of headers and a matching line of JSONPath expressions", path)
+	}
+
+	var columns []customColumn
+	for i, header := range lines[0] {
+		column, err := newCustomColumn(header, lines[1][i])
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// newCustomColumn accepts the same relaxed paths as kubectl: "{.a.b}",
+// ".a.b" and "a.b" are equivalent.
+func newCustomColumn(header, path string) (customColumn, error) {
+	path = strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+	if !strings.HasPrefix(path, ".") {
+		path = "." + path
+	}
+
+	parser := jsonpath.New(header).AllowMissingKeys(true)
+	if err := parser.Parse("{" + path + "}"); err != nil {
+		return customColumn{}, fmt.Errorf("invalid JSONPath for column %s: %v", header, err)
+	}
+	return customColumn{header: header, path: parser}, nil
+}
+
+// printCustomColumns evaluates the columns against every item of list.
+func printCustomColumns(w io.Writer, columns []customColumn, list runtime.Object) error {
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.header
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, item := range items {
+		content, err := toUnstructured(item)
+		if err != nil {
+			return err
+		}
+
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			results, err := col.path.FindResults(content)
+			if err != nil {
+				return err
+			}
+			cells[i] = formatResults(results)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func toUnstructured(obj runtime.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+func formatResults(results [][]reflect.Value) string {
+	var values []string
+	for _, result := range results {
+		for _, value := range result {
+			values = append(values, fmt.Sprintf("%v", value.Interface()))
+		}
+	}
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ",")
+}
+
+// listDynamic lists any resource the API server knows about, including
+// CRDs, through the dynamic client. There are no typed rows for these, so
+// only object-level formats, custom-columns and raw, can render them.
+func listDynamic(ctx context.Context, p *printer, src *source, resourceType, namespace string, opts metav1.ListOptions) {
+	gvr, namespaced, err := src.resolveResource(resourceType)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	list, err := fetch(src, &unstructured.UnstructuredList{}, namespace, opts, func() (*unstructured.UnstructuredList, error) {
+		if namespaced {
+			return src.dynamic.Resource(gvr).Namespace(namespace).List(ctx, opts)
+		}
+		return src.dynamic.Resource(gvr).List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	if p.format == "raw" {
+		err = printRaw(p.w, list, p.managedFields)
+	} else {
+		err = printCustomColumns(p.w, p.customColumns, list)
+	}
+	if err != nil {
+		handleError(err)
+	}
+}
+
+// resolveResource maps a name such as "widgets" or "widgets.example.com"
+// to its API resource using discovery.
+func (src *source) resolveResource(resourceType string) (schema.GroupVersionResource, bool, error) {
+	gvr, err := src.mapper.ResourceFor(schema.ParseGroupResource(resourceType).WithVersion(""))
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("resource %s not found on server: %v", resourceType, err)
+	}
+	gvk, err := src.mapper.KindFor(gvr)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	mapping, err := src.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	return gvr, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// digest stands in for a data value, so that Secret values are compared
+// without being kept.
+type digest [sha256.Size]byte
+
+// dataTracker remembers the data keys of every ConfigMap and Secret seen
+// during a watch and reports which keys were added, removed or modified
+// since the previous tick. Objects seen for the first time only set the
+// baseline. It outlives the per-tick printer.
+type dataTracker struct {
+	digests map[string]map[string]digest // per "kind namespace/name[@cluster]"
+}
+
+func newDataTracker() *dataTracker {
+	return &dataTracker{digests: map[string]map[string]digest{}}
+}
+
+// observeConfigMaps and observeSecrets take the cluster of a
+// TYPE@CLUSTER section, to keep its objects apart.
+func (t *dataTracker) observeConfigMaps(w io.Writer, cluster string, configMaps []corev1.ConfigMap) {
+	for _, cm := range configMaps {
+		data := map[string]digest{}
+		for key, value := range cm.Data {
+			data[key] = sha256.Sum256([]byte(value))
+		}
+		for key, value := range cm.BinaryData {
+			data[key] = sha256.Sum256(value)
+		}
+		t.observe(w, "configmap "+atCluster(cm.Namespace+"/"+cm.Name, cluster), data)
+	}
+}
+
+func (t *dataTracker) observeSecrets(w io.Writer, cluster string, secrets []corev1.Secret) {
+	for _, secret := range secrets {
+		data := map[string]digest{}
+		for key, value := range secret.Data {
+			data[key] = sha256.Sum256(value)
+		}
+		t.observe(w, "secret "+atCluster(secret.Namespace+"/"+secret.Name, cluster), data)
+	}
+}
+
+// observe prints one line naming the keys of object that changed, never
+// their values.
+func (t *dataTracker) observe(w io.Writer, object string, data map[string]digest) {
+	previous, seen := t.digests[object]
+	t.digests[object] = data
+	if !seen {
+		return
+	}
+
+	var changes []string
+	for key, value := range data {
+		if old, ok := previous[key]; !ok {
+			changes = append(changes, "key "+key+" added")
+		} else if old != value {
+			changes = append(changes, "key "+key+" changed")
+		}
+	}
+	for key := range previous {
+		if _, ok := data[key]; !ok {
+			changes = append(changes, "key "+key+" removed")
+		}
+	}
+	if len(changes) > 0 {
+		sort.Strings(changes)
+		fmt.Fprintf(w, "DATA CHANGE %s: %s\n", object, strings.Join(changes, ", "))
+	}
+}
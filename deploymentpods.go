@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// podReplicaSetColumn tells a rollout's old and new pods apart.
+var podReplicaSetColumn = column[PodRow]{"REPLICASET", 0, func(r PodRow) string {
+	for _, owner := range r.objectMeta().GetOwnerReferences() {
+		if owner.Kind == "ReplicaSet" {
+			return owner.Name
+		}
+	}
+	return "<none>"
+}, nil}
+
+// printDeploymentPods follows a deployment to the pods it owns through its
+// ReplicaSets. Ownership is checked by UID rather than by selector alone,
+// so pods of another controller with overlapping labels are left out.
+func printDeploymentPods(ctx context.Context, p *printer, src *source, namespace, name string) {
+	deployment, err := getDeployment(ctx, src, namespace, name)
+	if err != nil {
+		handleError(err)
+		return
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		handleError(err)
+		return
+	}
+	opts := metav1.ListOptions{LabelSelector: selector.String()}
+
+	replicaSets, err := fetch(src, &appsv1.ReplicaSetList{}, namespace, opts, func() (*appsv1.ReplicaSetList, error) {
+		return src.clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	owned := map[types.UID]bool{}
+	for _, rs := range replicaSets.Items {
+		if ownedBy(rs.ObjectMeta, deployment.UID) {
+			owned[rs.UID] = true
+		}
+	}
+
+	pods, err := fetch(src, &corev1.PodList{}, namespace, opts, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	var items []corev1.Pod
+	for _, pod := range pods.Items {
+		for _, owner := range pod.OwnerReferences {
+			if owned[owner.UID] {
+				items = append(items, pod)
+				break
+			}
+		}
+	}
+	pods.Items = items
+
+	columns := podColumns
+	if p.wide || p.columnFields("pods") != nil {
+		columns = append(columns[:len(columns):len(columns)], podWideColumns...)
+	}
+	columns = append(columns[:len(columns):len(columns)], podReplicaSetColumn)
+	var rows []PodRow
+	for _, pod := range pods.Items {
+		rows = append(rows, newPodRow(pod))
+	}
+
+	if p.format == "table" {
+		row := newDeploymentRow(*deployment)
+		fmt.Fprintf(p.w, "\nDeployment: %s/%s (ready %s, up-to-date %d, available %d)\n", deployment.Namespace, deployment.Name, row.Ready, row.UpToDate, row.Available)
+		fmt.Fprintf(p.w, "Selector:   %s\n", selector)
+	}
+	printRows(p, "pods", pods, columns, rows)
+}
+
+func getDeployment(ctx context.Context, src *source, namespace, name string) (*appsv1.Deployment, error) {
+	opts := metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()}
+	deployments, err := fetch(src, &appsv1.DeploymentList{}, namespace, opts, func() (*appsv1.DeploymentList, error) {
+		return src.clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(deployments.Items) == 0 {
+		return nil, fmt.Errorf("deployment %s not found in namespace %s", name, namespace)
+	}
+	return &deployments.Items[0], nil
+}
+
+func ownedBy(object metav1.ObjectMeta, uid types.UID) bool {
+	for _, owner := range object.OwnerReferences {
+		if owner.UID == uid {
+			return true
+		}
+	}
+	return false
+}
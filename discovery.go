@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// views are -resource names this tool synthesizes rather than lists.
+var views = []string{"overview", "images", "pvc-pending"}
+
+// checkResource makes sure the server serves resourceType, including its
+// API group for known types, so a typo or a CRD that is not installed
+// fails with suggestions instead of an empty or unsupported listing.
+// Discovery errors other than a missing resource are left to the first
+// List call to report.
+func (src *source) checkResource(resourceType string) error {
+	for _, view := range views {
+		if resourceType == view {
+			return nil
+		}
+	}
+	resource := schema.ParseGroupResource(resourceType)
+	if info, ok := lookupResource(resourceType); ok {
+		resource = info.gvr.GroupResource()
+	}
+	if _, err := src.mapper.ResourceFor(resource.WithVersion("")); err == nil || !meta.IsNoMatchError(err) {
+		return nil
+	}
+
+	message := fmt.Sprintf("resource %s not found on server", resourceType)
+	if resource.Resource == "componentstatuses" {
+		return fmt.Errorf("%s", componentStatusesRemoved)
+	}
+	if resource.Group == gatewayGroup {
+		return fmt.Errorf("%s", gatewayAPIMissing)
+	}
+	if matches := closeMatches(resourceType, src.serverResourceNames()); len(matches) > 0 {
+		message += "; did you mean " + strings.Join(matches, ", ") + "?"
+	}
+	return fmt.Errorf("%s", message)
+}
+
+// serverResourceNames lists every name the server accepts for a resource:
+// plural, short names and plural.group. A partial discovery failure still
+// returns the groups that answered.
+func (src *source) serverResourceNames() []string {
+	lists, _ := src.clientset.Discovery().ServerPreferredResources()
+	var names []string
+	for _, list := range lists {
+		group := ""
+		if gv, err := schema.ParseGroupVersion(list.GroupVersion); err == nil {
+			group = gv.Group
+		}
+		for _, resource := range list.APIResources {
+			names = append(names, resource.Name)
+			names = append(names, resource.ShortNames...)
+			if group != "" {
+				names = append(names, resource.Name+"."+group)
+			}
+		}
+	}
+	return names
+}
+
+// localResourceNames are the -resource names known without a server.
+func localResourceNames() []string {
+	names := append([]string{}, views...)
+	for _, info := range resourceRegistry {
+		names = append(names, info.name)
+		names = append(names, info.aliases...)
+	}
+	return names
+}
+
+// closeMatches returns up to three candidates within a small edit distance
+// of name, containing it, or naming the same resource in another group,
+// closest first.
+func closeMatches(name string, candidates []string) []string {
+	type match struct {
+		name     string
+		distance int
+	}
+	var matches []match
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		if seen[candidate] || candidate == name {
+			continue
+		}
+		seen[candidate] = true
+		distance := editDistance(name, candidate)
+		sameResource := strings.Contains(name, ".") && strings.Split(name, ".")[0] == strings.Split(candidate, ".")[0]
+		if distance <= 2 || sameResource || (len(name) >= 3 && strings.Contains(candidate, name)) {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var names []string
+	for i := 0; i < len(matches) && i < 3; i++ {
+		names = append(names, matches[i].name)
+	}
+	return names
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
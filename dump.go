@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// dump holds the objects decoded from a kubectl YAML/JSON dump.
+type dump []runtime.Object
+
+// readDump decodes every object in the file at path, or in stdin for "-".
+// Documents may be single objects, typed lists (PodList, ...) or the
+// generic v1 List kubectl emits. The dump is never nil, even when empty,
+// as a nil dump means reading the live cluster.
+func readDump(path string) (dump, error) {
+	var f io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		f = file
+	} else {
+		path = "stdin"
+	}
+
+	objects := dump{}
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %v", path, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		decoded, err := decodeObjects(doc)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %v", path, err)
+		}
+		objects = append(objects, decoded...)
+	}
+	return objects, nil
+}
+
+func decodeObjects(data []byte) ([]runtime.Object, error) {
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// kubectl wraps mixed output in a v1 List whose items are still raw
+	if list, ok := obj.(*corev1.List); ok {
+		var objects []runtime.Object
+		for _, item := range list.Items {
+			decoded, err := decodeObjects(item.Raw)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, decoded...)
+		}
+		return objects, nil
+	}
+
+	if meta.IsListType(obj) {
+		return meta.ExtractList(obj)
+	}
+	return []runtime.Object{obj}, nil
+}
+
+// into fills list (e.g. *corev1.PodList) with the dumped objects of the
+// matching kind, applying the label and field selectors of opts the way
+// the API server would. An empty namespace matches every namespace.
+func (d dump) into(list runtime.Object, namespace string, opts metav1.ListOptions) error {
+	kinds, _, err := scheme.Scheme.ObjectKinds(list)
+	if err != nil {
+		return err
+	}
+	kind := strings.TrimSuffix(kinds[0].Kind, "List")
+
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return err
+	}
+	fieldSelector, err := fields.ParseSelector(opts.FieldSelector)
+	if err != nil {
+		return err
+	}
+
+	var items []runtime.Object
+	for _, obj := range d {
+		objKinds, _, err := scheme.Scheme.ObjectKinds(obj)
+		if err != nil || objKinds[0].Kind != kind {
+			continue
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			continue
+		}
+		if namespace != "" && accessor.GetNamespace() != "" && accessor.GetNamespace() != namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(accessor.GetLabels())) {
+			continue
+		}
+		if !fieldSelector.Matches(objectFields(obj, accessor)) {
+			continue
+		}
+		items = append(items, obj)
+	}
+	return meta.SetList(list, items)
+}
+
+// objectFields returns the fields a field selector can match on.
+func objectFields(obj runtime.Object, accessor metav1.Object) fields.Set {
+	set := fields.Set{
+		"metadata.name":      accessor.GetName(),
+		"metadata.namespace": accessor.GetNamespace(),
+	}
+	if pod, ok := obj.(*corev1.Pod); ok {
+		set["spec.nodeName"] = pod.Spec.NodeName
+	}
+	return set
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// kubectl get pods -o yaml in an empty namespace
+const emptyList = `apiVersion: v1
+kind: List
+items: []
+metadata:
+  resourceVersion: ""
+`
+
+func TestReadDumpEmptyList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.yaml")
+	if err := os.WriteFile(path, []byte(emptyList), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := readDump(path)
+	if err != nil {
+		t.Fatalf("readDump: %v", err)
+	}
+	if objects == nil {
+		t.Fatal("readDump returned a nil dump for an empty List, which reads as the live cluster")
+	}
+
+	pods := &corev1.PodList{}
+	if err := objects.into(pods, "", metav1.ListOptions{}); err != nil {
+		t.Fatalf("into: %v", err)
+	}
+	if len(pods.Items) != 0 {
+		t.Errorf("got %d pods, want 0", len(pods.Items))
+	}
+}
+
+func TestReadDumpEmptyStdin(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+
+	objects, err := readDump("-")
+	if err != nil {
+		t.Fatalf("readDump: %v", err)
+	}
+	if objects == nil {
+		t.Fatal("readDump returned a nil dump for empty stdin")
+	}
+}
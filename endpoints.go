@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var endpointColumns = []column[EndpointRow]{
+	{"POD", 40, func(r EndpointRow) string { return r.Pod }, nil},
+	{"READY", 10, func(r EndpointRow) string { return r.Ready }, nil},
+	{"IP", 20, func(r EndpointRow) string { return r.IP }, nil},
+	{"NODE", 30, func(r EndpointRow) string { return r.Node }, nil},
+	{"ENDPOINT", 12, func(r EndpointRow) string { return r.Endpoint }, nil},
+	{"SERVING", 8, func(r EndpointRow) string { return r.Serving }, nil},
+	{"TERMINATING", 11, func(r EndpointRow) string { return r.Terminating }, nil},
+}
+
+// printServiceEndpoints joins a service to its EndpointSlices and the pods
+// its selector matches, so a pod that matches but is not serving (or an
+// endpoint with no pod behind it) stands out.
+func printServiceEndpoints(ctx context.Context, p *printer, src *source, namespace, name string) {
+	svc, err := getService(ctx, src, namespace, name)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	sliceOpts := metav1.ListOptions{LabelSelector: labels.Set{discoveryv1.LabelServiceName: name}.String()}
+	slices, err := fetch(src, &discoveryv1.EndpointSliceList{}, namespace, sliceOpts, func() (*discoveryv1.EndpointSliceList, error) {
+		return src.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, sliceOpts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var pods []corev1.Pod
+	if len(svc.Spec.Selector) > 0 {
+		podOpts := metav1.ListOptions{LabelSelector: labels.Set(svc.Spec.Selector).String()}
+		podList, err := fetch(src, &corev1.PodList{}, namespace, podOpts, func() (*corev1.PodList, error) {
+			return src.clientset.CoreV1().Pods(namespace).List(ctx, podOpts)
+		})
+		if err != nil {
+			handleError(err)
+			return
+		}
+		pods = podList.Items
+	}
+
+	rows := buildEndpointRows(slices.Items, pods)
+
+	if p.format == "table" {
+		selector := "<none> (endpoints are managed manually)"
+		if len(svc.Spec.Selector) > 0 {
+			selector = labels.Set(svc.Spec.Selector).String()
+		}
+		ready, terminating, serving := 0, 0, 0
+		for _, row := range rows {
+			if row.Endpoint == "ready" {
+				ready++
+			}
+			if row.Terminating == "true" {
+				terminating++
+				if row.Serving == "true" {
+					serving++
+				}
+			}
+		}
+
+		fmt.Fprintf(p.w, "\nService:  %s/%s (%s, %s)\n", svc.Namespace, svc.Name, svc.Spec.Type, svc.Spec.ClusterIP)
+		fmt.Fprintf(p.w, "Selector: %s\n", selector)
+		fmt.Fprintf(p.w, "Ready:    %d/%d\n", ready, len(rows))
+		if terminating > 0 {
+			// still serving while terminating: existing connections drain
+			// but new ones may still land on the pod
+			fmt.Fprintf(p.w, "Terminating: %d, %d still serving\n", terminating, serving)
+		}
+	}
+	printRows(p, "endpoints", nil, endpointColumns, rows)
+}
+
+func getService(ctx context.Context, src *source, namespace, name string) (*corev1.Service, error) {
+	opts := metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()}
+	services, err := fetch(src, &corev1.ServiceList{}, namespace, opts, func() (*corev1.ServiceList, error) {
+		return src.clientset.CoreV1().Services(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(services.Items) == 0 {
+		return nil, fmt.Errorf("service %s not found in namespace %s", name, namespace)
+	}
+	return &services.Items[0], nil
+}
+
+// buildEndpointRows lists every selected pod with its endpoint state
+// ("ready", "not-ready", "terminating" or "missing"), followed by endpoints
+// that do not point at any selected pod.
+func buildEndpointRows(slices []discoveryv1.EndpointSlice, pods []corev1.Pod) []EndpointRow {
+	type endpointState struct {
+		ip         string
+		conditions discoveryv1.EndpointConditions
+	}
+	byPod := map[string]endpointState{}
+	var orphans []EndpointRow
+
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			ip := "<none>"
+			if len(endpoint.Addresses) > 0 {
+				ip = endpoint.Addresses[0]
+			}
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				byPod[endpoint.TargetRef.Name] = endpointState{ip: ip, conditions: endpoint.Conditions}
+				continue
+			}
+
+			node := "<none>"
+			if endpoint.NodeName != nil {
+				node = *endpoint.NodeName
+			}
+			row := EndpointRow{Pod: "<none>", Ready: "-", IP: ip, Node: node}
+			setEndpointConditions(&row, endpoint.Conditions)
+			orphans = append(orphans, row)
+		}
+	}
+
+	var rows []EndpointRow
+	for _, pod := range pods {
+		row := EndpointRow{
+			Pod:         pod.Name,
+			Ready:       fmt.Sprintf("%d/%d", getReadyContainers(pod.Status.ContainerStatuses), len(pod.Spec.Containers)),
+			IP:          pod.Status.PodIP,
+			Node:        pod.Spec.NodeName,
+			Endpoint:    "missing",
+			Serving:     "-",
+			Terminating: "-",
+		}
+		if state, ok := byPod[pod.Name]; ok {
+			setEndpointConditions(&row, state.conditions)
+			if row.IP == "" {
+				row.IP = state.ip
+			}
+			delete(byPod, pod.Name)
+		}
+		if row.IP == "" {
+			row.IP = "<none>"
+		}
+		if row.Node == "" {
+			row.Node = "<none>"
+		}
+		rows = append(rows, row)
+	}
+
+	// endpoints whose pod no longer matches the selector
+	for podName, state := range byPod {
+		row := EndpointRow{Pod: podName, Ready: "-", IP: state.ip, Node: "<none>"}
+		setEndpointConditions(&row, state.conditions)
+		rows = append(rows, row)
+	}
+	return append(rows, orphans...)
+}
+
+// setEndpointConditions fills in row's ENDPOINT, SERVING and TERMINATING
+// from the endpoint's conditions. Unset conditions read as the API
+// defines them: ready and serving if not terminating.
+func setEndpointConditions(row *EndpointRow, conditions discoveryv1.EndpointConditions) {
+	terminating := conditions.Terminating != nil && *conditions.Terminating
+	ready := conditions.Ready == nil || *conditions.Ready
+	serving := ready
+	if conditions.Serving != nil {
+		serving = *conditions.Serving
+	}
+
+	row.Endpoint = "not-ready"
+	if terminating {
+		row.Endpoint = "terminating"
+	} else if ready {
+		row.Endpoint = "ready"
+	}
+	row.Serving = strconv.FormatBool(serving)
+	row.Terminating = strconv.FormatBool(terminating)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var envVarColumns = []column[EnvVarRow]{
+	{"POD", 40, func(r EnvVarRow) string { return r.Pod }, nil},
+	{"CONTAINER", 25, func(r EnvVarRow) string { return r.Container }, nil},
+	{"NAME", 35, func(r EnvVarRow) string { return r.Name }, nil},
+	{"SOURCE", 17, func(r EnvVarRow) string { return r.Source }, nil},
+	{"FROM", 0, func(r EnvVarRow) string { return orNone(r.From) }, nil},
+}
+
+// listEnvVars prints, for -show-env, one line per environment variable of
+// every init and regular container, with where its value comes from.
+// Values themselves are never read: a literal is only reported as one,
+// and a ConfigMap or Secret reference names the object and key.
+func listEnvVars(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	pods, err := fetch(src, &corev1.PodList{}, namespace, opts, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []EnvVarRow
+	for _, pod := range pods.Items {
+		containers := append(pod.Spec.InitContainers[:len(pod.Spec.InitContainers):len(pod.Spec.InitContainers)], pod.Spec.Containers...)
+		for _, c := range containers {
+			rows = append(rows, buildEnvVarRows(pod, c)...)
+		}
+	}
+	printRows(p, "env", nil, envVarColumns, rows)
+}
+
+// buildEnvVarRows lists the container's envFrom sources first, then its
+// env entries, in the order the kubelet applies them: a later entry wins
+// over an earlier one of the same name.
+func buildEnvVarRows(pod corev1.Pod, c corev1.Container) []EnvVarRow {
+	row := func(name, source, from string) EnvVarRow {
+		return EnvVarRow{
+			Namespace: pod.Namespace,
+			Pod:       pod.Name,
+			Container: c.Name,
+			Name:      name,
+			Source:    source,
+			From:      from,
+		}
+	}
+
+	var rows []EnvVarRow
+	for _, envFrom := range c.EnvFrom {
+		// every key of the object, prefixed
+		name := envFrom.Prefix + "*"
+		switch {
+		case envFrom.ConfigMapRef != nil:
+			rows = append(rows, row(name, "configMapRef", "configmap "+envFrom.ConfigMapRef.Name+optionalRef(envFrom.ConfigMapRef.Optional)))
+		case envFrom.SecretRef != nil:
+			rows = append(rows, row(name, "secretRef", "secret "+envFrom.SecretRef.Name+optionalRef(envFrom.SecretRef.Optional)))
+		}
+	}
+
+	for _, env := range c.Env {
+		from := env.ValueFrom
+		switch {
+		case from == nil:
+			rows = append(rows, row(env.Name, "literal", ""))
+		case from.ConfigMapKeyRef != nil:
+			ref := from.ConfigMapKeyRef
+			rows = append(rows, row(env.Name, "configMapKeyRef", "configmap "+ref.Name+" key "+ref.Key+optionalRef(ref.Optional)))
+		case from.SecretKeyRef != nil:
+			ref := from.SecretKeyRef
+			rows = append(rows, row(env.Name, "secretKeyRef", "secret "+ref.Name+" key "+ref.Key+optionalRef(ref.Optional)))
+		case from.FieldRef != nil:
+			rows = append(rows, row(env.Name, "fieldRef", from.FieldRef.FieldPath))
+		case from.ResourceFieldRef != nil:
+			// unset, the container is this one
+			ref, container := from.ResourceFieldRef, from.ResourceFieldRef.ContainerName
+			if container == "" {
+				container = c.Name
+			}
+			rows = append(rows, row(env.Name, "resourceFieldRef", container+" "+ref.Resource))
+		default:
+			rows = append(rows, row(env.Name, "<unknown>", ""))
+		}
+	}
+	return rows
+}
+
+// optionalRef marks a reference the pod starts without when it is missing.
+func optionalRef(optional *bool) string {
+	if optional != nil && *optional {
+		return " (optional)"
+	}
+	return ""
+}
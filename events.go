@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// watchEvents prints one line per add, update and delete until ctx is done.
+// The label selector is applied server-side, so the API server turns an
+// object that stops matching into a delete; those are told apart from real
+// deletions by checking whether the object still exists.
+//
+// With a coalesce window, events are buffered and each object's latest
+// state is printed once per window, so bursts of updates stay readable.
+// beat, when set, reports on stderr whether the stream is still healthy.
+func watchEvents(ctx context.Context, w io.Writer, src *source, resourceType, namespace, selector string, coalesce time.Duration, beat *heartbeat) error {
+	info, ok := lookupResource(resourceType)
+	if !ok {
+		return fmt.Errorf("unsupported resource type for -events: %s", resourceType)
+	}
+
+	clientset := src.clientset
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = selector
+		}))
+	generic, err := factory.ForResource(info.gvr)
+	if err != nil {
+		return err
+	}
+
+	emit := func(event string, obj interface{}) { printEvent(w, event, obj, 1) }
+	if coalesce > 0 {
+		c := &coalescer{pending: map[string]*pendingEvent{}}
+		emit = c.add
+		stop, flushed := make(chan struct{}), make(chan struct{})
+		go func() {
+			c.run(stop, w, coalesce)
+			close(flushed)
+		}()
+		// runs after the informers are shut down, so that no event comes
+		// in after the last flush
+		defer func() {
+			close(stop)
+			<-flushed
+		}()
+	}
+
+	started := time.Now()
+	informer := generic.Informer()
+	_, err = informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			event := "ADDED"
+			if selector != "" && !isInInitialList && createdBefore(obj, started) {
+				event = "JOINED"
+			}
+			emit(event, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			emit("MODIFIED", newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			event := "DELETED"
+			if selector != "" {
+				if exists, err := objectExists(ctx, src.dynamic, info.gvr, obj); err == nil && exists {
+					event = "LEFT"
+				}
+			}
+			emit(event, obj)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// the informer retries a failed list or watch on its own, so without
+	// this a broken stream would look like a quiet one
+	stream := &streamHealth{}
+	err = informer.SetWatchErrorHandlerWithContext(func(ctx context.Context, r *cache.Reflector, err error) {
+		stream.failed(err)
+		cache.DefaultWatchErrorHandler(ctx, r, err)
+	})
+	if err != nil {
+		return err
+	}
+	if beat != nil {
+		go beat.run(ctx, resourceType+" events", func() string {
+			connected := stateConnected
+			if src.reconnect != nil {
+				connected = src.reconnect.status()
+			}
+			return stream.status(informer.HasSynced(), connected)
+		})
+	}
+
+	scope := "in namespace " + namespace
+	if namespace == "" {
+		scope = "cluster-wide"
+	}
+	fmt.Fprintf(w, "Streaming %s events %s (Ctrl+C to exit)...\n", resourceType, scope)
+	factory.Start(ctx.Done())
+	for synced, ok := range factory.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			return fmt.Errorf("failed to sync informer for %s", synced)
+		}
+	}
+	if src.health != nil {
+		src.health.ready.Store(true)
+	}
+	<-ctx.Done()
+	factory.Shutdown()
+	return nil
+}
+
+// createdBefore reports whether obj already existed when the watch started,
+// meaning it was relabeled into the selector rather than newly created.
+func createdBefore(obj interface{}, started time.Time) bool {
+	object, ok := obj.(metav1.Object)
+	return ok && object.GetCreationTimestamp().Time.Before(started)
+}
+
+// objectExists fetches obj again without the label selector, through the
+// dynamic client so that any registered group is read from its own API
+// path.
+func objectExists(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, obj interface{}) (bool, error) {
+	object, ok := obj.(metav1.Object)
+	if !ok {
+		return false, nil
+	}
+
+	_, err := client.Resource(gvr).Namespace(object.GetNamespace()).Get(ctx, object.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// streamHealth is how the -events stream is doing, for the heartbeat.
+type streamHealth struct {
+	mu  sync.Mutex
+	err error // the last failed list or watch since the previous status
+}
+
+func (s *streamHealth) failed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// status is the error of the list or watch that failed since the previous
+// call, "syncing" until the first list is in, or else connected.
+func (s *streamHealth) status(synced bool, connected string) string {
+	s.mu.Lock()
+	err := s.err
+	s.err = nil
+	s.mu.Unlock()
+	switch {
+	case err != nil:
+		return "watch failing, retrying: " + err.Error()
+	case !synced:
+		return "syncing"
+	}
+	return connected
+}
+
+// pendingEvent is an object's buffered event: its latest state and how
+// many events were folded into it.
+type pendingEvent struct {
+	event string
+	obj   interface{}
+	count int
+}
+
+// coalescer buffers informer events for -coalesce-window.
+type coalescer struct {
+	mu      sync.Mutex
+	pending map[string]*pendingEvent
+}
+
+// add folds an event into the object's pending one. Updates keep the
+// event that brought the object in, so an object added and then modified
+// within one window still reads ADDED.
+func (c *coalescer) add(event string, obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if pending, ok := c.pending[key]; ok {
+		if event != "MODIFIED" || pending.event == "DELETED" || pending.event == "LEFT" {
+			pending.event = event
+		}
+		pending.obj = obj
+		pending.count++
+		return
+	}
+	c.pending[key] = &pendingEvent{event, obj, 1}
+}
+
+// run prints the pending events once per window until stop is closed,
+// and then whatever is still pending, so a Ctrl+C or -max-duration does
+// not lose the last window.
+func (c *coalescer) run(stop <-chan struct{}, w io.Writer, window time.Duration) {
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			c.flush(w)
+			return
+		case <-ticker.C:
+			c.flush(w)
+		}
+	}
+}
+
+// flush prints the pending events in key order.
+func (c *coalescer) flush(w io.Writer) {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = map[string]*pendingEvent{}
+	c.mu.Unlock()
+
+	for _, key := range sortedKeys(pending) {
+		printEvent(w, pending[key].event, pending[key].obj, pending[key].count)
+	}
+}
+
+// printEvent prints one event line; count is how many events were
+// coalesced into it.
+func printEvent(w io.Writer, event string, obj interface{}, count int) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+
+	note := ""
+	switch event {
+	case "JOINED":
+		note = " (now matches selector)"
+	case "LEFT":
+		note = " (left selector)"
+	}
+	if count > 1 {
+		note += fmt.Sprintf(" (%d events)", count)
+	}
+	fmt.Fprintf(w, "%s %-10s %-50s %s%s\n", time.Now().Format("15:04:05"), event, key, getObjectStatus(obj), note)
+}
+
+// getObjectStatus summarises obj the same way its table row would.
+func getObjectStatus(obj interface{}) string {
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		return string(o.Status.Phase)
+	case *appsv1.Deployment:
+		return fmt.Sprintf("%d/%d ready", o.Status.ReadyReplicas, getDesiredReplicas(*o))
+	case *corev1.Service:
+		return string(o.Spec.Type)
+	case *corev1.Node:
+		return getNodeStatus(*o)
+	}
+	return ""
+}
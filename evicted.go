@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// isEvicted reports whether the kubelet evicted the pod under node
+// pressure. Evicted pods stay Failed until someone deletes them.
+func isEvicted(pod corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == "Evicted"
+}
+
+// evictedAt is when the pod was evicted, from its DisruptionTarget
+// condition where the kubelet sets one, else when the pod was created.
+func evictedAt(pod corev1.Pod) time.Time {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.DisruptionTarget && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
+var evictedPodColumns = []column[EvictedPodRow]{
+	{"NAMESPACE", 20, func(r EvictedPodRow) string { return r.Namespace }, nil},
+	{"NAME", 40, func(r EvictedPodRow) string { return r.Name }, nil},
+	{"NODE", 30, func(r EvictedPodRow) string { return orNone(r.Node) }, nil},
+	{"EVICTED", 10, func(r EvictedPodRow) string { return r.Age }, ignored[EvictedPodRow]},
+	{"MESSAGE", 0, func(r EvictedPodRow) string { return r.Message }, nil},
+}
+
+// printEvictedPods is the -cleanup-evicted dry run: it lists the evicted
+// pods and, in table mode, the kubectl commands that would delete them.
+// It deletes nothing itself.
+func printEvictedPods(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	pods, err := fetch(src, &corev1.PodList{}, namespace, opts, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []EvictedPodRow
+	byNamespace := map[string][]string{}
+	for _, pod := range pods.Items {
+		if !isEvicted(pod) {
+			continue
+		}
+		at := evictedAt(pod)
+		rows = append(rows, EvictedPodRow{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Node:      pod.Spec.NodeName,
+			Message:   pod.Status.Message,
+			EvictedAt: at,
+			Age:       formatAge(at),
+		})
+		byNamespace[pod.Namespace] = append(byNamespace[pod.Namespace], pod.Name)
+	}
+	printRows(p, "evicted pods", pods, evictedPodColumns, rows)
+
+	if p.format != "table" || len(rows) == 0 {
+		return
+	}
+	fmt.Fprintln(p.w, "\nTo delete them:")
+	for _, ns := range sortedKeys(byNamespace) {
+		fmt.Fprintf(p.w, "  kubectl delete pod -n %s %s\n", ns, strings.Join(byNamespace[ns], " "))
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podExplainColumn is added by -explain.
+var podExplainColumn = column[PodRow]{"EXPLANATION", 0, func(r PodRow) string { return r.Explanation }, nil}
+
+// podWarnings returns the message of the most recent Warning event for
+// each pod in namespace, keyed by namespace/name so that same-named pods
+// of different namespaces keep their own.
+func podWarnings(ctx context.Context, src *source, namespace string) (map[string]string, error) {
+	events, err := latestWarnings(ctx, src, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := map[string]string{}
+	for _, event := range events {
+		if event.InvolvedObject.Kind == "Pod" {
+			warnings[event.InvolvedObject.Namespace+"/"+event.InvolvedObject.Name] = event.Message
+		}
+	}
+	return warnings, nil
+}
+
+// explainPod says in plain words why a pod is not simply running, using
+// its container states and the last warning event recorded for it. It
+// returns "" for pods that need no explanation.
+func explainPod(pod corev1.Pod, warning string) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if waiting := status.State.Waiting; waiting != nil {
+			switch waiting.Reason {
+			case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+				return fmt.Sprintf("%s: cannot pull image %s: %s", waiting.Reason, status.Image, firstNonEmpty(warning, waiting.Message))
+			case "CrashLoopBackOff":
+				if last := status.LastTerminationState.Terminated; last != nil {
+					return fmt.Sprintf("CrashLoopBackOff: container %s keeps exiting (last exit code %d, %s)", status.Name, last.ExitCode, last.Reason)
+				}
+				return fmt.Sprintf("CrashLoopBackOff: container %s keeps exiting", status.Name)
+			case "CreateContainerConfigError", "CreateContainerError", "RunContainerError":
+				return fmt.Sprintf("%s: container %s cannot start: %s", waiting.Reason, status.Name, firstNonEmpty(waiting.Message, warning))
+			}
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+			return fmt.Sprintf("OOMKilled: container %s was killed for exceeding its memory limit", status.Name)
+		}
+	}
+
+	if getPodStatus(pod) == "NotReady" {
+		explanation := "NotReady: running but a readiness gate is not met, so it gets no service traffic"
+		if containers := notReadyContainers(pod); len(containers) > 0 {
+			explanation = fmt.Sprintf("NotReady: running but container %s is not ready, so it gets no service traffic", strings.Join(containers, ", "))
+		}
+		if warning != "" {
+			// usually the failing readiness probe
+			explanation += ": " + warning
+		}
+		return explanation
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			return fmt.Sprintf("%s: cannot be scheduled: %s", condition.Reason, condition.Message)
+		}
+	}
+
+	switch pod.Status.Phase {
+	case corev1.PodFailed, corev1.PodUnknown:
+		if pod.Status.Reason != "" {
+			return fmt.Sprintf("%s: %s", pod.Status.Reason, firstNonEmpty(pod.Status.Message, warning))
+		}
+	case corev1.PodPending:
+		return warning
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return "<no details>"
+}
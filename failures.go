@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// reportedErrors are the errors handleError printed during the current
+// tick, so a run over several resource types can tell which failed.
+var reportedErrors []error
+
+// sectionFailure is a resource type that could not be listed.
+type sectionFailure struct {
+	resource string
+	err      error
+}
+
+// printFailures prints one footer naming every resource type that failed,
+// after the ones that were listed.
+func printFailures(p *printer, failed []sectionFailure) {
+	names := make([]string, len(failed))
+	for i, f := range failed {
+		reason := string(errors.ReasonForError(f.err))
+		if reason == "" || reason == "Unknown" {
+			reason = "error"
+		}
+		names[i] = fmt.Sprintf("%s (%s)", f.resource, reason)
+	}
+	w := p.w
+	if p.format != "table" {
+		w = os.Stderr
+	}
+	fmt.Fprintf(w, "\nFailed to list: %s\n", strings.Join(names, ", "))
+}
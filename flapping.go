@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// flapDetector counts STATUS changes per object over the watch session for
+// -detect-flapping. An object flaps when it changes more than threshold
+// times within window. It outlives the per-tick printer.
+type flapDetector struct {
+	threshold int
+	window    time.Duration
+	last      map[string]string
+	changes   map[string][]time.Time
+	peak      map[string]int // most changes seen within one window
+}
+
+func newFlapDetector(threshold int, window time.Duration) *flapDetector {
+	return &flapDetector{
+		threshold: threshold,
+		window:    window,
+		last:      map[string]string{},
+		changes:   map[string][]time.Time{},
+		peak:      map[string]int{},
+	}
+}
+
+type flapper struct {
+	key     string
+	changes int
+}
+
+// recordFlaps notes the STATUS changes since the previous tick and prints
+// the objects that just changed again while already flapping. Deleted
+// objects keep their history for the end-of-session summary.
+func recordFlaps[T any](w io.Writer, d *flapDetector, kind, cluster string, columns []column[T], rows []T) {
+	statuses, ok := rowStatuses(columns, rows)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	var current []flapper
+	for _, row := range statuses {
+		key := strings.TrimSuffix(kind, "s") + " " + atCluster(row.key, cluster)
+		previous, seen := d.last[key]
+		d.last[key] = row.status
+		if !seen || previous == row.status {
+			continue
+		}
+
+		// keep only the changes still inside the window
+		recent := append(d.changes[key], now)
+		for len(recent) > 0 && now.Sub(recent[0]) > d.window {
+			recent = recent[1:]
+		}
+		d.changes[key] = recent
+		if len(recent) > d.peak[key] {
+			d.peak[key] = len(recent)
+		}
+		if len(recent) > d.threshold {
+			current = append(current, flapper{key, len(recent)})
+		}
+	}
+
+	sortFlappers(current)
+	for _, f := range current {
+		fmt.Fprintf(w, "FLAPPING %s changed status %d times in the last %s\n", f.key, f.changes, d.window)
+	}
+}
+
+// printSummary lists the worst flappers of the session, by the most
+// changes each made within one window.
+func (d *flapDetector) printSummary(w io.Writer) {
+	var flappers []flapper
+	for key, peak := range d.peak {
+		if peak > d.threshold {
+			flappers = append(flappers, flapper{key, peak})
+		}
+	}
+	if len(flappers) == 0 {
+		fmt.Fprintf(w, "\nNo object changed status more than %d times within %s\n", d.threshold, d.window)
+		return
+	}
+
+	sortFlappers(flappers)
+	if len(flappers) > 10 {
+		flappers = flappers[:10]
+	}
+	fmt.Fprintf(w, "\nTop flapping objects (most status changes within %s):\n", d.window)
+	for _, f := range flappers {
+		fmt.Fprintf(w, "  %-50s %d\n", f.key, f.changes)
+	}
+}
+
+func sortFlappers(flappers []flapper) {
+	sort.Slice(flappers, func(i, j int) bool {
+		if flappers[i].changes != flappers[j].changes {
+			return flappers[i].changes > flappers[j].changes
+		}
+		return flappers[i].key < flappers[j].key
+	})
+}
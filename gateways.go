@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// gatewayGroup is the Gateway API's group. Its types are CRDs, listed
+// through the dynamic client in whichever version the cluster serves.
+const gatewayGroup = "gateway.networking.k8s.io"
+
+// gatewayAPIMissing is shown where the Gateway API CRDs are not installed.
+const gatewayAPIMissing = "the Gateway API CRDs (" + gatewayGroup + ") are not installed on this cluster; see https://gateway-api.sigs.k8s.io/guides/#installing-gateway-api"
+
+var gatewayColumns = []column[GatewayRow]{
+	{"NAME", 40, func(r GatewayRow) string { return r.Name }, nil},
+	{"CLASS", 20, func(r GatewayRow) string { return r.Class }, nil},
+	{"ADDRESSES", 25, func(r GatewayRow) string { return joinOrNone(r.Addresses) }, nil},
+	{"PROGRAMMED", 11, func(r GatewayRow) string { return r.Programmed }, nil},
+	{"AGE", 10, func(r GatewayRow) string { return r.Age }, ignored[GatewayRow]},
+	{"LISTENERS", 0, func(r GatewayRow) string { return joinOrNone(r.Listeners) }, nil},
+}
+
+var httpRouteColumns = []column[HTTPRouteRow]{
+	{"NAME", 40, func(r HTTPRouteRow) string { return r.Name }, nil},
+	{"HOSTNAMES", 30, func(r HTTPRouteRow) string { return joinOrNone(r.Hostnames) }, nil},
+	{"PARENTS", 30, func(r HTTPRouteRow) string { return joinOrNone(r.Parents) }, nil},
+	{"AGE", 10, func(r HTTPRouteRow) string { return r.Age }, ignored[HTTPRouteRow]},
+	{"BACKENDS", 0, func(r HTTPRouteRow) string { return joinOrNone(r.Backends) }, nil},
+}
+
+func listGateways(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	gateways, err := listGatewayAPI(ctx, src, "gateways", namespace, opts)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []GatewayRow
+	for i := range gateways.Items {
+		rows = append(rows, newGatewayRow(&gateways.Items[i]))
+	}
+	printRows(p, "gateways", gateways, gatewayColumns, rows)
+}
+
+func listHTTPRoutes(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	routes, err := listGatewayAPI(ctx, src, "httproutes", namespace, opts)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []HTTPRouteRow
+	for i := range routes.Items {
+		rows = append(rows, newHTTPRouteRow(&routes.Items[i]))
+	}
+	printRows(p, "httproutes", routes, httpRouteColumns, rows)
+}
+
+// listGatewayAPI lists a Gateway API resource, saying so plainly when its
+// CRD is not installed rather than reporting a missing resource.
+func listGatewayAPI(ctx context.Context, src *source, resource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if src.dynamic == nil {
+		return nil, fmt.Errorf("%s need a live cluster and cannot be read from -from-file", resource)
+	}
+	gvr, _, err := src.resolveResource(resource + "." + gatewayGroup)
+	if err != nil {
+		return nil, fmt.Errorf("%s", gatewayAPIMissing)
+	}
+	list, err := fetch(src, &unstructured.UnstructuredList{}, namespace, opts, func() (*unstructured.UnstructuredList, error) {
+		return src.dynamic.Resource(gvr).Namespace(namespace).List(ctx, opts)
+	})
+	// the CRD was removed after discovery was cached
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%s", gatewayAPIMissing)
+	}
+	return list, err
+}
+
+func newGatewayRow(gateway *unstructured.Unstructured) GatewayRow {
+	row := GatewayRow{
+		rowMeta:    rowMeta{gateway},
+		Namespace:  gateway.GetNamespace(),
+		Name:       gateway.GetName(),
+		Programmed: "Unknown",
+		Created:    gateway.GetCreationTimestamp().Time,
+		Age:        formatAge(gateway.GetCreationTimestamp().Time),
+	}
+	row.Class, _, _ = unstructured.NestedString(gateway.Object, "spec", "gatewayClassName")
+
+	listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+	for _, item := range listeners {
+		listener, _ := item.(map[string]interface{})
+		name, _, _ := unstructured.NestedString(listener, "name")
+		protocol, _, _ := unstructured.NestedString(listener, "protocol")
+		port, _, _ := unstructured.NestedInt64(listener, "port")
+		entry := fmt.Sprintf("%s %s/%d", name, protocol, port)
+		if hostname, _, _ := unstructured.NestedString(listener, "hostname"); hostname != "" {
+			entry += " " + hostname
+		}
+		row.Listeners = append(row.Listeners, entry)
+	}
+
+	addresses, _, _ := unstructured.NestedSlice(gateway.Object, "status", "addresses")
+	for _, item := range addresses {
+		address, _ := item.(map[string]interface{})
+		if value, _, _ := unstructured.NestedString(address, "value"); value != "" {
+			row.Addresses = append(row.Addresses, value)
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(gateway.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, _ := item.(map[string]interface{})
+		if kind, _, _ := unstructured.NestedString(condition, "type"); kind == "Programmed" {
+			row.Programmed, _, _ = unstructured.NestedString(condition, "status")
+		}
+	}
+	return row
+}
+
+func newHTTPRouteRow(route *unstructured.Unstructured) HTTPRouteRow {
+	row := HTTPRouteRow{
+		rowMeta:   rowMeta{route},
+		Namespace: route.GetNamespace(),
+		Name:      route.GetName(),
+		Created:   route.GetCreationTimestamp().Time,
+		Age:       formatAge(route.GetCreationTimestamp().Time),
+	}
+	row.Hostnames, _, _ = unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+
+	// unset namespaces are the route's own
+	parents, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	for _, item := range parents {
+		parent, _ := item.(map[string]interface{})
+		row.Parents = appendUnique(row.Parents, gatewayRef(parent, route.GetNamespace(), "sectionName"))
+	}
+
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	for _, item := range rules {
+		rule, _ := item.(map[string]interface{})
+		backends, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+		for _, item := range backends {
+			backend, _ := item.(map[string]interface{})
+			ref := gatewayRef(backend, route.GetNamespace(), "")
+			if kind, _, _ := unstructured.NestedString(backend, "kind"); kind != "" && kind != "Service" {
+				ref = kind + "/" + ref
+			}
+			if port, ok, _ := unstructured.NestedInt64(backend, "port"); ok {
+				ref += ":" + strconv.FormatInt(port, 10)
+			}
+			row.Backends = appendUnique(row.Backends, ref)
+		}
+	}
+	return row
+}
+
+// gatewayRef formats a Gateway API object reference as namespace/name,
+// followed by its section, if any, as namespace/name#section.
+func gatewayRef(ref map[string]interface{}, defaultNamespace, sectionField string) string {
+	name, _, _ := unstructured.NestedString(ref, "name")
+	namespace, _, _ := unstructured.NestedString(ref, "namespace")
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	formatted := namespace + "/" + name
+	if sectionField != "" {
+		if section, _, _ := unstructured.NestedString(ref, sectionField); section != "" {
+			formatted += "#" + section
+		}
+	}
+	return formatted
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+// healthStatus backs the -health-addr probes. ready flips once the first
+// List against the API server has succeeded and stays set, so a transient
+// API error later does not get the monitor restarted.
+type healthStatus struct {
+	ready atomic.Bool
+}
+
+// serveHealth serves /healthz and /readyz on addr in the background. The
+// listener is opened before returning so a bad address fails at startup.
+func serveHealth(addr string, health *healthStatus) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !health.ready.Load() {
+			http.Error(w, "not ready: no successful List from the API server yet", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	go http.Serve(listener, mux)
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// heartbeat prints a dim "still watching" line on stderr every so often
+// when the screen is not redrawn, so a quiet -watch-on-change-only or
+// -events stream can be told apart from a hung one.
+type heartbeat struct {
+	w     io.Writer
+	every time.Duration
+	dim   bool
+	last  time.Time
+}
+
+func newHeartbeat(every time.Duration) *heartbeat {
+	return &heartbeat{w: os.Stderr, every: every, dim: term.IsTerminal(int(os.Stderr.Fd())), last: time.Now()}
+}
+
+// beat prints the line if the last one was at least every ago. status says
+// how the watch is doing, e.g. "connected".
+func (h *heartbeat) beat(what, status string) {
+	now := time.Now()
+	if now.Sub(h.last) < h.every {
+		return
+	}
+	h.last = now
+	line := fmt.Sprintf("· %s still watching %s, %s", now.Format("15:04:05"), what, status)
+	if h.dim {
+		line = "\033[2m" + line + "\033[0m"
+	}
+	fmt.Fprintln(h.w, line)
+}
+
+// run beats on its own until ctx is done, for -events where nothing ticks.
+// status is asked for the watch's state at every beat.
+func (h *heartbeat) run(ctx context.Context, what string, status func() string) {
+	ticker := time.NewTicker(h.every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.beat(what, status())
+		}
+	}
+}
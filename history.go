@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// revisionAnnotation is set by the deployment controller on a deployment
+// and each of its ReplicaSets.
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// changeCauseAnnotation is what kubectl --record and kubectl annotate
+// leave on a deployment to say why it was changed. The deployment
+// controller copies it to the ReplicaSet of each revision.
+const changeCauseAnnotation = "kubernetes.io/change-cause"
+
+var historyColumns = []column[RevisionRow]{
+	{"REVISION", 10, func(r RevisionRow) string {
+		if r.Current {
+			return strconv.FormatInt(r.Revision, 10) + "*"
+		}
+		return strconv.FormatInt(r.Revision, 10)
+	}, nil},
+	{"REPLICASET", 40, func(r RevisionRow) string { return r.ReplicaSet }, nil},
+	{"CREATED", 10, func(r RevisionRow) string { return r.Age }, ignored[RevisionRow]},
+	{"IMAGES", 40, func(r RevisionRow) string { return strings.Join(r.Images, ",") }, nil},
+	{"CHANGE-CAUSE", 0, func(r RevisionRow) string { return orNone(r.ChangeCause) }, nil},
+}
+
+// printDeploymentHistory lists the revisions of a deployment, one per
+// ReplicaSet it owns, like kubectl rollout history. The current revision
+// is marked with *.
+func printDeploymentHistory(ctx context.Context, p *printer, src *source, namespace, name string) {
+	deployment, err := getDeployment(ctx, src, namespace, name)
+	if err != nil {
+		handleError(err)
+		return
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		handleError(err)
+		return
+	}
+	opts := metav1.ListOptions{LabelSelector: selector.String()}
+	replicaSets, err := fetch(src, &appsv1.ReplicaSetList{}, namespace, opts, func() (*appsv1.ReplicaSetList, error) {
+		return src.clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	current := deployment.Annotations[revisionAnnotation]
+	var rows []RevisionRow
+	for _, rs := range replicaSets.Items {
+		if !ownedBy(rs.ObjectMeta, deployment.UID) {
+			continue
+		}
+		if row, ok := newRevisionRow(rs, current); ok {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Revision < rows[j].Revision })
+	printRows(p, "revisions", nil, historyColumns, rows)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pulledMessage matches the kubelet's Pulled event, which has said how
+// long the pull took since Kubernetes 1.24:
+// Successfully pulled image "nginx:1.25" in 2.1s (2.1s including waiting)
+var pulledMessage = regexp.MustCompile(`^Successfully pulled image "([^"]+)"(?: in ([0-9][0-9.a-zµ]*))?`)
+
+// imagePullTracker reports, for -slow-image-pulls, the image pulls during
+// the watch that took longer than threshold, from the Pulling and Pulled
+// events of the listed pods. It outlives the per-tick printer.
+type imagePullTracker struct {
+	threshold time.Duration
+	started   time.Time
+	done      map[string]bool // Pulled events already counted
+	pulls     int
+	slow      int
+	slowest   time.Duration
+	slowestAt string // "image on node"
+}
+
+func newImagePullTracker(threshold time.Duration) *imagePullTracker {
+	return &imagePullTracker{threshold: threshold, started: time.Now(), done: map[string]bool{}}
+}
+
+// observe prints one line for each slow pull completed since the previous
+// tick.
+func (t *imagePullTracker) observe(ctx context.Context, w io.Writer, src *source, namespace string, pods []corev1.Pod) error {
+	events, err := fetch(src, &corev1.EventList{}, namespace, metav1.ListOptions{}, func() (*corev1.EventList, error) {
+		return src.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return err
+	}
+
+	nodes := map[string]string{}
+	for _, pod := range pods {
+		nodes[pod.Namespace+"/"+pod.Name] = pod.Spec.NodeName
+	}
+	// when the message has no duration, fall back to the time since the
+	// container's Pulling event
+	pulling := map[string]time.Time{}
+	for _, event := range events.Items {
+		if event.Reason == "Pulling" {
+			pulling[pullKey(event)] = eventTime(event)
+		}
+	}
+
+	for _, event := range events.Items {
+		involved := event.InvolvedObject
+		key := involved.Namespace + "/" + involved.Name
+		node, listed := nodes[key]
+		at := eventTime(event)
+		// older pulls happened before anyone was watching
+		if event.Reason != "Pulled" || involved.Kind != "Pod" || !listed || at.Before(t.started.Truncate(time.Second)) {
+			continue
+		}
+		seen := string(event.UID) + " " + at.String()
+		if t.done[seen] {
+			continue
+		}
+
+		match := pulledMessage.FindStringSubmatch(event.Message)
+		if match == nil {
+			// "already present on machine" is not a pull
+			continue
+		}
+		image := match[1]
+		took, err := time.ParseDuration(match[2])
+		if err != nil {
+			start, ok := pulling[pullKey(event)]
+			if !ok || start.After(at) {
+				continue
+			}
+			took = at.Sub(start)
+		}
+		t.done[seen] = true
+
+		t.pulls++
+		if took > t.slowest {
+			t.slowest, t.slowestAt = took, image+" on "+orNone(node)
+		}
+		if took <= t.threshold {
+			continue
+		}
+		t.slow++
+		container := strings.TrimSuffix(strings.TrimPrefix(involved.FieldPath, "spec.containers{"), "}")
+		fmt.Fprintf(w, "SLOW PULL pod %s container %s on %s: pulled %s in %s\n", key, container, orNone(node), image, took.Round(100*time.Millisecond))
+	}
+	return nil
+}
+
+// pullKey pairs a Pulled event with the Pulling event of the same
+// container.
+func pullKey(event corev1.Event) string {
+	involved := event.InvolvedObject
+	return involved.Namespace + "/" + involved.Name + " " + involved.FieldPath
+}
+
+// printSummary prints the session's pull count and the slowest pull.
+func (t *imagePullTracker) printSummary(w io.Writer) {
+	if t.pulls == 0 {
+		fmt.Fprintln(w, "\nNo image was pulled during the watch")
+		return
+	}
+	fmt.Fprintf(w, "\nImage pulls during the watch: %d, %d slower than %s; slowest %s (%s)\n",
+		t.pulls, t.slow, t.threshold, t.slowest.Round(100*time.Millisecond), t.slowestAt)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var imageColumns = []column[ImageRow]{
+	{"IMAGE", 60, func(r ImageRow) string { return r.Image }, nil},
+	{"PODS", 6, func(r ImageRow) string { return strconv.Itoa(r.Pods) }, nil},
+	{"NAMESPACES", 0, func(r ImageRow) string { return joinOrNone(r.Namespaces) }, nil},
+}
+
+// printImages lists every image the cluster's pods run, init and
+// ephemeral containers included, with how many pods and which namespaces
+// use it. filter keeps only images containing it.
+func printImages(ctx context.Context, p *printer, src *source, filter string, opts metav1.ListOptions) {
+	pods, err := fetch(src, &corev1.PodList{}, "", opts, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods("").List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	images := map[string]*ImageRow{}
+	namespaces := map[string]map[string]bool{}
+	for _, pod := range pods.Items {
+		// a pod running an image in several containers counts once
+		seen := map[string]bool{}
+		for _, row := range buildContainerRows(pod) {
+			if seen[row.Image] || !strings.Contains(row.Image, filter) {
+				continue
+			}
+			seen[row.Image] = true
+			if images[row.Image] == nil {
+				images[row.Image] = &ImageRow{Image: row.Image}
+				namespaces[row.Image] = map[string]bool{}
+			}
+			images[row.Image].Pods++
+			if !namespaces[row.Image][pod.Namespace] {
+				namespaces[row.Image][pod.Namespace] = true
+				images[row.Image].Namespaces = append(images[row.Image].Namespaces, pod.Namespace)
+			}
+		}
+	}
+
+	rows := make([]ImageRow, 0, len(images))
+	for _, image := range sortedKeys(images) {
+		sort.Strings(images[image].Namespaces)
+		rows = append(rows, *images[image])
+	}
+	printRows(p, "images", nil, imageColumns, rows)
+}
@@ -4,238 +4,1404 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
+
+	"golang.org/x/term"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 func main() {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
+	// installed as kubectl-monitor, kubectl runs it for "kubectl monitor"
+	plugin := strings.HasPrefix(filepath.Base(os.Args[0]), "kubectl-")
+	if plugin {
+		flag.Usage = func() {
+			fmt.Fprintln(flag.CommandLine.Output(), "Usage of kubectl monitor:")
+			flag.PrintDefaults()
+		}
 	}
-	namespace := flag.String("namespace", "default", "namespace to watch")
-	resourceType := flag.String("resource", "deployments", "resource to watch (pods, deployments, services, etc.)")
+
+	kubeconfig := flag.String("kubeconfig", "", "path to the kubeconfig file (default: $KUBECONFIG, or ~/.kube/config)")
+	kubeContext := flag.String("context", "", "kubeconfig context to use (default: the current context)")
+	namespace := flag.String("namespace", "default", "namespace to watch; as a kubectl plugin, the current context's namespace by default")
+	flag.StringVar(namespace, "n", "default", "shorthand for -namespace")
+	resourceType := flag.String("resource", "deployments", "resource to watch (pods, deployments, services, etc.); several comma-separated types are shown in sections")
+	expandSections := flag.String("expand", "", "with several -resource types, comma-separated types to show as full tables instead of collapsed counts")
 	watch := flag.Bool("watch", false, "watch resources in real time")
 	interval := flag.Int("interval", 5, "interval in seconds for watching resources")
+	watchCount := flag.Int("watch-count", 0, "number of watch iterations before exiting (0 = watch forever, implies -watch)")
+	fromFile := flag.String("from-file", "", "read resources from a kubectl YAML/JSON dump instead of the cluster; - reads stdin")
+	flag.StringVar(fromFile, "f", "", "shorthand for -from-file")
+	selector := flag.String("selector", "", "label selector to filter resources (e.g. app=web,tier!=cache)")
+	flag.StringVar(selector, "l", "", "shorthand for -selector")
+	name := flag.String("name", "", "only show the object with this name")
+	node := flag.String("node", "", "with -resource pods, only show pods scheduled on this node")
+	showEndpoints := flag.Bool("endpoints", false, "with -resource service -name NAME, show the service's selector and backing pods")
+	rollout := flag.Bool("rollout", false, "for deployments, show the gap between desired and actual replicas with an estimated completion")
+	stallTimeout := flag.Duration("stall-timeout", 5*time.Minute, "with -rollout, flag a rollout as stalled after this long without progress")
+	validate := flag.Bool("validate", false, "with -resource services, warn about duplicate nodePorts or clusterIPs and services selecting the same pods")
+	onlyProblems := flag.Bool("only-problems", false, "list only unhealthy objects of the -resource types (default: pods, deployments, services and nodes), each with its latest Warning event")
+	summary := flag.Bool("summary", false, "print one health verdict for the namespace's pods, deployments and services and the cluster's nodes; with -o json, a versioned document for alerting")
+	tree := flag.Bool("tree", false, "show the namespace's workloads as a tree of owners: Deployment → ReplicaSet → Pod, StatefulSet → Pod, DaemonSet → Pod")
+	diffNamespace := flag.String("diff-namespace", "", "compare the -resource objects of -namespace with this namespace's: objects only one has and key fields that differ")
+	usedBy := flag.Bool("used-by", false, "with -resource configmap or secret and -name NAME, list the pods that mount it or read it into their environment")
+	fieldManagers := flag.Bool("field-managers", false, "with -name, show which field managers own which fields of the object, from its managedFields")
+	cleanupEvicted := flag.Bool("cleanup-evicted", false, "with -resource pods, list the evicted pods and the kubectl commands to delete them; nothing is deleted")
+	statusFilter := flag.String("status", "", "with -resource pods, only show pods in these comma-separated statuses, e.g. Running,Pending or CrashLoopBackOff")
+	showContainers := flag.Bool("containers", false, "with -resource pods, list every container, including init and ephemeral (kubectl debug) containers")
+	showEnv := flag.Bool("show-env", false, "with -resource pods, list every container's environment variable names and where their values come from, never the values")
+	history := flag.Bool("history", false, "with -resource deployment -name NAME, list its revisions like kubectl rollout history")
+	showPods := flag.Bool("show-pods", false, "with -resource deployment -name NAME, list the pods it owns through its ReplicaSets")
+	events := flag.Bool("events", false, "stream add/update/delete events from an informer instead of polling")
+	coalesceWindow := flag.Duration("coalesce-window", 500*time.Millisecond, "with -events, print each object's latest state once per window instead of every update; 0 prints every event")
+	nodeConditions := flag.Bool("node-conditions", false, "show MemoryPressure, DiskPressure, PIDPressure and NetworkUnavailable conditions for nodes")
+	waitReady := flag.Bool("wait-ready", false, "with -resource services, wait until every service has a ready endpoint and exit 0 (2 on timeout)")
+	timeout := flag.Duration("timeout", 0, "maximum time to wait with -wait-ready, and the deadline of each API request, also sent to the server as timeoutSeconds (0 = no limit)")
+	maxDuration := flag.Duration("max-duration", 0, "end the whole session after this long, exiting 0, e.g. for bounded monitoring runs in CI (0 = no limit)")
+	onChangeOnly := flag.Bool("watch-on-change-only", false, "in watch mode, print the first snapshot and then only what changed")
+	heartbeatEvery := flag.Duration("heartbeat", 30*time.Second, "with -watch-on-change-only, -output-file or -events, print a dim still-watching line on stderr this often")
+	noHeartbeat := flag.Bool("no-heartbeat", false, "do not print the -heartbeat line")
+	maxOutputRate := flag.Int("max-output-rate", 0, "with -events or -watch-on-change-only, print at most this many update lines per second and summarize the rest; 0 is unlimited")
+	adaptive := flag.Bool("adaptive", false, "in watch mode, back off the interval while nothing changes and return to -interval on change")
+	maxInterval := flag.Duration("max-interval", time.Minute, "longest interval -adaptive backs off to")
+	showManagedFields := flag.Bool("show-managed-fields", false, "keep metadata.managedFields in -o raw output")
+	logFile := flag.String("log-file", "", "append every tick as a JSON line to this file, rotating it by size")
+	sqlitePath := flag.String("sqlite", "", "record every listed object's status, restarts and ready count each tick to this SQLite database")
+	auditFile := flag.String("audit-file", "", "append every observed STATUS change to this file as JSON lines, whatever the output format")
+	logMaxSize := flag.Int("log-max-size-mb", 100, "size in megabytes at which -log-file is rotated")
+	logMaxFiles := flag.Int("log-max-files", 5, "number of rotated -log-file backups to keep")
+	transitions := flag.Bool("transitions", false, "in watch mode, report every STATUS change with how long the previous state was held")
+	detectFlapping := flag.Bool("detect-flapping", false, "in watch mode, report objects whose STATUS changes more than -flap-threshold times within -flap-window")
+	flapThreshold := flag.Int("flap-threshold", 3, "status changes within -flap-window above which an object is flapping")
+	flapWindow := flag.Duration("flap-window", 10*time.Minute, "window over which -detect-flapping counts status changes")
+	detectRestartSpikes := flag.Bool("detect-restart-spikes", false, "in watch mode, warn when a pod's restarts within -spike-window jump well above its rate so far in the session")
+	restartRateWindow := flag.Duration("restart-rate-window", 5*time.Minute, "in watch mode, window over which the pods' RESTARTS/MIN is averaged")
+	spikeWindow := flag.Duration("spike-window", 5*time.Minute, "window over which -detect-restart-spikes counts restarts")
+	spikeFactor := flag.Float64("spike-factor", 3, "how many times the baseline restarts within -spike-window make a spike")
+	spikeMinRestarts := flag.Int("spike-min-restarts", 3, "fewest restarts within -spike-window reported as a spike")
+	notify := flag.Bool("notify", false, "in watch mode, ring the terminal bell and show a desktop notification when a pod, node or deployment becomes unhealthy")
+	notifyCooldown := flag.Duration("notify-cooldown", 10*time.Minute, "least time between two -notify notifications for the same object")
+	alertWebhook := flag.String("alert-webhook", "", "in watch mode, POST a JSON alert to this URL when a node goes from Ready to NotReady")
+	slowImagePulls := flag.Duration("slow-image-pulls", 0, "in watch mode, report image pulls of the listed pods that took longer than this, from their Pulled events (0 disables)")
+	sparklineTicks := flag.Int("sparkline", 0, "in watch mode, show each resource type's count over the last N ticks as a sparkline after its total (0 disables)")
+	readiness := flag.Bool("readiness", false, "in watch mode, with -resource pods, print a line when a pod becomes Ready, with how long it took since creation, and when a pod loses readiness")
+	schedulingLatency := flag.Bool("scheduling-latency", false, "in watch mode, report how long pods created during the watch took to be scheduled and to start running, with p50/p90 when the watch ends")
+	explain := flag.Bool("explain", false, "add a plain-words explanation of non-obvious pod states, from container states and recent events")
+	asUser := flag.String("as", "", "username to impersonate for the API calls, like kubectl --as")
+	asGroups := flag.String("as-group", "", "comma-separated groups to impersonate, with -as")
+	asUID := flag.String("as-uid", "", "UID to impersonate, with -as")
+	proxyURL := flag.String("proxy-url", "", "reach the API server through this proxy (http, https or socks5); HTTPS_PROXY and NO_PROXY are honored without it")
+	healthAddr := flag.String("health-addr", "", "serve /healthz and /readyz on this address, e.g. :8080")
+	pprofAddr := flag.String("pprof-addr", "", "serve net/http/pprof under /debug/pprof/ on this address, e.g. localhost:6060")
+	cpuProfile := flag.String("cpuprofile", "", "write a CPU profile of the run to this file")
+	memProfile := flag.String("memprofile", "", "write a heap profile to this file when the run ends")
+	pushGatewayURL := flag.String("push-gateway", "", "push object counts to this Prometheus Pushgateway after every tick, e.g. http://pushgateway:9091")
+	pushJob := flag.String("push-job", "k8s-monitor", "job label for -push-gateway")
+	pushInstance := flag.String("push-instance", "", "instance label for -push-gateway (default: the hostname)")
+	showLatency := flag.Bool("show-latency", false, "print how long each API List call took to stderr (rolling average in watch mode)")
+	outputFile := flag.String("output-file", "", "write the rendered output to this file instead of stdout")
+	output := flag.String("output", "table", "output format: table, markdown, json, raw, yaml (with -name), mermaid (services), custom-columns=SPEC or custom-columns-file=PATH")
+	stripStatus := flag.Bool("strip-status", false, "leave status out of -o yaml")
+	flag.StringVar(output, "o", "table", "shorthand for -output")
+	groupBy := flag.String("group-by", "", "in table output, group rows by this field (see -fields), e.g. zone")
+	sortBy := flag.String("sort-by", "", "sort rows by this field (see -fields), numbers numerically and age by creation time, newest first")
+	reverse := flag.Bool("reverse", false, "reverse the -sort-by order, e.g. oldest first with -sort-by age")
+	pin := flag.String("pin", "", "comma-separated object names always shown first, in this order")
+	stickySort := flag.Bool("sticky-sort", false, "in watch mode, keep rows where they first appeared instead of re-sorting every tick, and mark rows whose -sort-by value changed with *")
+	flag.DurationVar(&terminatingGrace, "terminating-grace", terminatingGrace, "how long past its grace period a pod may stay Terminating before -only-problems and -summary report it")
+	flag.DurationVar(&notReadyGrace, "not-ready-grace", notReadyGrace, "how long a Running pod may stay not ready before -only-problems and -summary report it")
+	columnFields := flag.String("fields", "", "comma-separated columns to show, in order (e.g. name,status,age); overrides the config file's fields for this run")
+	configFile := flag.String("config", defaultConfigPath(), "config file with the columns to show per resource type")
+	wide := flag.Bool("wide", false, "show additional columns (pods: IP, NODE, QOS; deployments: CHANGE-CAUSE)")
+	showAnnotations := flag.String("show-annotations", "", "comma-separated annotation keys to show as extra columns")
+	imageFilter := flag.String("image-filter", "", "with images, only list images containing this substring")
+	replayFile := flag.String("replay", "", "replay the STATUS changes recorded in an -audit-file and exit")
+	replaySpeed := flag.Float64("replay-speed", 1, "with -replay, how many times faster than recorded to play back; 0 prints everything at once")
+	printSchemaFor := flag.String("print-schema", "", "print the JSON schema of -output json rows for a resource type and exit")
+	appendOutput := flag.Bool("append", false, "append each watch tick to -output-file instead of truncating it")
 
-	flag.Parse()
+	// "overview" and "images" are subcommands; the flags may follow them
+	args := os.Args[1:]
+	if len(args) > 0 && (args[0] == "overview" || args[0] == "images") {
+		*resourceType = args[0]
+		args = args[1:]
+	}
+	flag.CommandLine.Parse(args)
+
+	// Load the kubeconfig the way kubectl does: -kubeconfig, else every
+	// file in $KUBECONFIG, else ~/.kube/config
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = *kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: *kubeContext})
 
-	// Create the client configuration
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+	explicitNamespace := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "namespace" || f.Name == "n" {
+			explicitNamespace = true
+		}
+	})
+	if plugin && !explicitNamespace && *fromFile == "" {
+		// like kubectl, default to the context's namespace
+		if contextNamespace, _, err := clientConfig.Namespace(); err == nil {
+			*namespace = contextNamespace
+		}
+	}
+
+	explicitConfig := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			explicitConfig = true
+		}
+	})
+	settings, err := loadConfig(*configFile, explicitConfig)
 	if err != nil {
-		panic(err.Error())
+		fmt.Printf("Error: reading config: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Create the clientset
-	clientset, err := kubernetes.NewForConfig(config)
+	if *watchCount > 0 {
+		*watch = true
+	}
+
+	// -resource may name several types, watched together in sections
+	resources := splitList(*resourceType)
+	for i, resource := range resources {
+		// TYPE@CLUSTER reads that type from another cluster
+		resource, cluster, _ := strings.Cut(resource, "@")
+		if info, ok := lookupResource(resource); ok {
+			resources[i] = strings.TrimSuffix(info.name+"@"+cluster, "@")
+		}
+	}
+	*resourceType = strings.Join(resources, ",")
+	expand := map[string]bool{}
+	for _, resource := range splitList(*expandSections) {
+		resource, cluster, _ := strings.Cut(resource, "@")
+		if info, ok := lookupResource(resource); ok {
+			resource = info.name
+		}
+		expand[strings.TrimSuffix(resource+"@"+cluster, "@")] = true
+	}
+
+	// Cluster-scoped resources have no namespace; warn rather than let an
+	// explicit -namespace look like it filtered anything
+	if info, ok := lookupResource(strings.Split(*resourceType, "@")[0]); ok && !info.namespaced {
+		if explicitNamespace {
+			fmt.Fprintf(os.Stderr, "Warning: %s are cluster-scoped, ignoring -namespace %s\n", info.name, *namespace)
+		}
+		*namespace = ""
+	}
+
+	if *printSchemaFor != "" {
+		if err := printSchema(os.Stdout, *printSchemaFor); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *replayFile != "" {
+		if *replaySpeed < 0 {
+			fmt.Println("Error: -replay-speed cannot be negative")
+			os.Exit(exitError)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if err := replayAudit(ctx, os.Stdout, *replayFile, *replaySpeed); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	format, customColumns, err := parseOutput(*output)
 	if err != nil {
-		panic(err.Error())
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
 	}
 
-	ctx := context.Background()
+	src := &source{}
+	if *fromFile != "" {
+		objects, err := readDump(*fromFile)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		src.dump = objects
+	} else {
+		// Create the client configuration
+		config, err := clientConfig.ClientConfig()
+		if err != nil {
+			panic(err.Error())
+		}
 
-	// Get and display resources based on type
-	for {
+		if err := configureClient(config, *proxyURL, *asUser, *asUID, *asGroups, *timeout); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if *watch {
+			// a fresh loader, so the kubeconfig is read from disk again
+			src.credentials = &credentialReloader{load: func() (*rest.Config, error) {
+				return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: *kubeContext}).ClientConfig()
+			}}
+			config.Wrap(src.credentials.wrap)
+		}
+		if err := src.connect(config); err != nil {
+			panic(err.Error())
+		}
+	}
+
+	if *showLatency {
+		src.latency = &latencyStats{}
+	}
+	if *watch && src.dump == nil {
+		src.churn = newChurnCounter()
+		if *notify {
+			src.notify = newProblemNotifier(*notifyCooldown)
+		}
+	}
+
+	if *healthAddr != "" {
+		src.health = &healthStatus{}
+		if src.dump != nil {
+			// nothing to connect to, the dump loaded fine
+			src.health.ready.Store(true)
+		}
+		if err := serveHealth(*healthAddr, src.health); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *pprofAddr != "" {
+		if err := servePprof(*pprofAddr); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	profiles, err := startProfiler(*cpuProfile, *memProfile)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var gateway *pushGateway
+	if *pushGatewayURL != "" {
+		if *pushInstance == "" {
+			*pushInstance, _ = os.Hostname()
+		}
+		if gateway, err = newPushGateway(*pushGatewayURL, *pushJob, *pushInstance); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var logWriter io.Writer
+	if *logFile != "" {
+		rotating := &lumberjack.Logger{
+			Filename:   *logFile,
+			MaxSize:    *logMaxSize,
+			MaxBackups: *logMaxFiles,
+		}
+		defer rotating.Close()
+		logWriter = rotating
+	}
+
+	var audit *auditLog
+	if *auditFile != "" {
+		if audit, err = openAuditLog(*auditFile); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var samples *sampleStore
+	if *sqlitePath != "" {
+		if samples, err = openSampleStore(*sqlitePath); err != nil {
+			fmt.Printf("Error: opening %s: %v\n", *sqlitePath, err)
+			os.Exit(1)
+		}
+	}
+
+	var onChange *changeFilter
+	var throttle *outputThrottle
+	if *maxOutputRate > 0 {
+		throttle = newOutputThrottle(*maxOutputRate)
+	}
+	if *onChangeOnly {
+		onChange = &changeFilter{}
+	}
+
+	var statusLog *transitionLog
+	if *transitions && *watch {
+		// a redrawn screen or rewritten file would wipe each transition
+		// after one tick, so keep the latest ones in view instead
+		history := 10
+		if *onChangeOnly || (*outputFile != "" && *appendOutput) {
+			history = 0
+		}
+		statusLog = newTransitionLog(history)
+	}
+
+	var rollouts *rolloutTracker
+	if *rollout {
+		rollouts = newRolloutTracker(*stallTimeout)
+	}
+
+	var flapping *flapDetector
+	if *detectFlapping && *watch {
+		flapping = newFlapDetector(*flapThreshold, *flapWindow)
+	}
+
+	var restartSpikes *restartSpikeDetector
+	if *detectRestartSpikes && *watch {
+		restartSpikes = newRestartSpikeDetector(*spikeWindow, *spikeFactor, *spikeMinRestarts)
+	}
+
+	var nodeAlerts *nodeAlerter
+	var dataChanges *dataTracker
+	var restartRate *restartRateTracker
+	if *watch {
+		dataChanges = newDataTracker()
+		// a sliding rate would make every restarting pod a change each
+		// tick
+		if !*onChangeOnly {
+			restartRate = newRestartRateTracker(*restartRateWindow)
+		}
+		if *alertWebhook != "" {
+			if u, err := url.Parse(*alertWebhook); err != nil || u.Host == "" {
+				fmt.Printf("Error: invalid -alert-webhook URL %q\n", *alertWebhook)
+				os.Exit(1)
+			}
+		}
+		nodeAlerts = newNodeAlerter(*alertWebhook)
+	}
+
+	var scheduling *schedulingTracker
+	if *schedulingLatency && *watch {
+		scheduling = newSchedulingTracker()
+	}
+	var imagePulls *imagePullTracker
+	if *slowImagePulls > 0 && *watch {
+		imagePulls = newImagePullTracker(*slowImagePulls)
+	}
+	var sparklines *countHistory
+	if *sparklineTicks > 0 && *watch {
+		sparklines = newCountHistory(*sparklineTicks)
+	}
+	var readinessChanges *readinessFeed
+	if *readiness && *watch {
+		readinessChanges = newReadinessFeed(*outputFile == "" && term.IsTerminal(int(os.Stdout.Fd())))
+	}
+
+	var sorter *rowSorter
+	if *sortBy != "" || *pin != "" || (*stickySort && *watch) {
+		sorter = newRowSorter(*sortBy, *reverse, splitList(*pin), *stickySort && *watch)
+	}
+
+	sleep := time.Duration(*interval) * time.Second
+	var backoff *adaptiveInterval
+	if *adaptive {
+		src.changes = newChangeTracker()
+		backoff = newAdaptiveInterval(sleep, *maxInterval)
+	}
+
+	// Ctrl+C ends a watch after the current tick, so end-of-session
+	// summaries still get printed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *maxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, *maxDuration, timeLimit{"-max-duration", *maxDuration})
+		defer cancel()
+	}
+	if *watch && src.dump == nil {
+		src.reconnect = &reconnector{w: os.Stderr, done: ctx.Done()}
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: *selector}
+	var fieldSelectors []fields.Selector
+	if *name != "" {
+		fieldSelectors = append(fieldSelectors, fields.OneTermEqualSelector("metadata.name", *name))
+	}
+	if *node != "" {
+		// spec.nodeName is a pod field; other types reject the selector
+		if *resourceType != "pods" || *events {
+			fmt.Println("Error: -node only works with -resource pods, without -events")
+			os.Exit(1)
+		}
+		fieldSelectors = append(fieldSelectors, fields.OneTermEqualSelector("spec.nodeName", *node))
+	}
+	if len(fieldSelectors) > 0 {
+		listOpts.FieldSelector = fields.AndSelectors(fieldSelectors...).String()
+	}
+
+	if *events {
+		if src.clientset == nil {
+			fmt.Println("Error: -events needs a live cluster and cannot be combined with -from-file")
+			os.Exit(1)
+		}
+		w, closeOutput, err := openOutput(*outputFile, *appendOutput)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		var throttled *throttledWriter
+		if *maxOutputRate > 0 {
+			throttled = newOutputThrottle(*maxOutputRate).writer(w)
+			go throttled.run(ctx)
+			w = throttled
+		}
+		var beat *heartbeat
+		if !*noHeartbeat && *heartbeatEvery > 0 {
+			beat = newHeartbeat(*heartbeatEvery)
+		}
+		err = watchEvents(ctx, w, src, *resourceType, *namespace, *selector, *coalesceWindow, beat)
+		if throttled != nil {
+			throttled.flush()
+		}
+		closeOutput()
+		if err != nil {
+			handleError(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var statuses map[string]bool
+	if *statusFilter != "" {
+		if *resourceType != "pods" {
+			fmt.Println("Error: -status only works with -resource pods")
+			os.Exit(exitError)
+		}
+		if statuses, err = parseStatusFilter(*statusFilter); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+
+	if *showEndpoints && *name == "" {
+		fmt.Println("Error: -endpoints needs -resource service -name NAME")
+		os.Exit(exitError)
+	}
+	if *showPods && *name == "" {
+		fmt.Println("Error: -show-pods needs -resource deployment -name NAME")
+		os.Exit(exitError)
+	}
+	if *history && *name == "" {
+		fmt.Println("Error: -history needs -resource deployment -name NAME")
+		os.Exit(exitError)
+	}
+	if *diffNamespace != "" && (len(resources) != 1 || *diffNamespace == *namespace || *namespace == "") {
+		fmt.Println("Error: -diff-namespace needs a single -resource and a -namespace other than it")
+		os.Exit(exitError)
+	}
+	if *usedBy && (*name == "" || (*resourceType != "configmaps" && *resourceType != "secrets")) {
+		fmt.Println("Error: -used-by needs -resource configmap or secret and -name NAME")
+		os.Exit(exitError)
+	}
+	if *fieldManagers && (*name == "" || len(resources) != 1) {
+		fmt.Println("Error: -field-managers needs a single -resource and -name NAME")
+		os.Exit(exitError)
+	}
+
+	if format == "yaml" {
+		if *name == "" || len(resources) != 1 {
+			fmt.Println("Error: -o yaml needs a single -resource and -name NAME")
+			os.Exit(exitError)
+		}
+		w, closeOutput, err := openOutput(*outputFile, *appendOutput)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		err = printObjectYAML(ctx, w, src, resources[0], *namespace, *name, *showManagedFields, *stripStatus)
+		closeOutput()
+		if err != nil {
+			handleError(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if format == "mermaid" && *resourceType != "services" {
+		fmt.Println("Error: -o mermaid is only supported with -resource services")
+		os.Exit(exitError)
+	}
+
+	if *waitReady {
 		switch *resourceType {
-		case "pods", "pod":
-			listPods(ctx, clientset, *namespace)
-		case "deployments", "deployment":
-			listDeployments(ctx, clientset, *namespace)
-		case "services", "service":
-			listServices(ctx, clientset, *namespace)
-		case "configmaps", "configmap":
-			listConfigMaps(ctx, clientset, *namespace)
-		case "secrets", "secret":
-			listSecrets(ctx, clientset, *namespace)
-		case "nodes", "node":
-			listNodes(ctx, clientset)
+		case "services":
 		default:
-			fmt.Printf("Unsupported resource type: %s\n", *resourceType)
+			fmt.Println("Error: -wait-ready is only supported with -resource services")
+			os.Exit(exitError)
+		}
+		os.Exit(waitServicesReady(ctx, os.Stdout, src, *namespace, listOpts, time.Duration(*interval)*time.Second, *timeout))
+	}
+
+	// -resource TYPE@CLUSTER sections read from a source of their own,
+	// sharing the main one's trackers
+	clusters := map[string]*source{}
+	for _, resource := range resources {
+		_, name, ok := strings.Cut(resource, "@")
+		if !ok || clusters[name] != nil {
+			continue
+		}
+		if src.dump != nil {
+			fmt.Printf("Error: -resource %s needs a live cluster and cannot be combined with -from-file\n", resource)
+			os.Exit(1)
+		}
+		cluster, ok := settings.Clusters[name]
+		if !ok {
+			// not in the config file: a context of the main kubeconfig
+			cluster = ClusterConfig{Kubeconfig: *kubeconfig, Context: name}
+		}
+		config, err := cluster.clientConfig().ClientConfig()
+		if err == nil {
+			err = configureClient(config, *proxyURL, *asUser, *asUID, *asGroups, *timeout)
+		}
+		// the main kubeconfig's credentials are not this cluster's
+		clusterSrc := *src
+		clusterSrc.credentials, clusterSrc.cluster = nil, name
+		if err == nil {
+			err = clusterSrc.connect(config)
+		}
+		if err != nil {
+			fmt.Printf("Error: cluster %s: %v\n", name, err)
 			os.Exit(1)
 		}
+		clusters[name] = &clusterSrc
+	}
+
+	// Catch typos and missing CRDs before the first tick rather than
+	// listing nothing
+	if src.mapper != nil && !*tree && !*summary {
+		for _, resource := range resources {
+			resource, cluster, _ := strings.Cut(resource, "@")
+			checkSrc := src
+			if cluster != "" {
+				checkSrc = clusters[cluster]
+			}
+			if err := checkSrc.checkResource(resource); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	// Keyboard control starts after the first tick, once the flags have
+	// proven usable, so an early exit never leaves the terminal raw
+	var keys *keyboard
+
+	// the last tick's resource types that could not be listed
+	var failed []sectionFailure
+	var sections []string
+
+	var beat *heartbeat
+	if *watch && !*noHeartbeat && *heartbeatEvery > 0 {
+		beat = newHeartbeat(*heartbeatEvery)
+	}
+
+	// a watched namespace being deleted ends the watch
+	var namespaceGone *namespaceWatch
+	if *watch && src.dump == nil && *namespace != "" {
+		namespaceGone = &namespaceWatch{name: *namespace}
+	}
+
+	// Get and display resources based on type
+	for iteration := 1; ; iteration++ {
+		if namespaceGone != nil {
+			if reason := namespaceGone.check(ctx, src); reason != "" && !namespaceGone.seen {
+				fmt.Printf("Error: %s\n", reason)
+				os.Exit(exitError)
+			} else if reason != "" {
+				fmt.Printf("\nWatch ended: %s\n", reason)
+				break
+			}
+		}
+
+		// Redraw over the previous tick in watch mode, unless the output is
+		// going to a file where the escape codes would only clutter the
+		// report
+		redraw := *watch && *outputFile == "" && !*onChangeOnly
+		if redraw {
+			fmt.Print("\033[H")
+			scope := "in namespace " + *namespace
+			if *namespace == "" {
+				scope = "cluster-wide"
+			}
+			help := "Ctrl+C to exit"
+			if keys != nil {
+				help = "p pause, space refresh, r resume, q exit"
+			}
+			status := ""
+			if src.reconnect != nil {
+				status = ", " + src.reconnect.status()
+			}
+			fmt.Printf("Watching %s %s every %s%s (%s)...\033[K\r\n", *resourceType, scope, sleep, status, help)
+			if src.churn != nil {
+				fmt.Printf("%s\033[K\r\n", src.churn.header())
+			}
+		}
+
+		w, closeOutput, err := openOutput(*outputFile, *appendOutput)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if redraw {
+			w = eraseLines{w}
+		}
+
+		p := &printer{
+			w:             w,
+			format:        format,
+			fields:        splitList(*columnFields),
+			savedFields:   settings.Fields,
+			customColumns: customColumns,
+			annotations:   splitList(*showAnnotations),
+			onChange:      onChange,
+			throttle:      throttle,
+			wide:          *wide,
+			log:           logWriter,
+			managedFields: *showManagedFields,
+			transitions:   statusLog,
+			explain:       *explain,
+			metrics:       gateway,
+			restartSpikes: restartSpikes,
+			restartRate:   restartRate,
+			sorter:        sorter,
+			scheduling:    scheduling,
+			imagePulls:    imagePulls,
+			readiness:     readinessChanges,
+			sparklines:    sparklines,
+			statuses:      statuses,
+			nodeAlerts:    nodeAlerts,
+			dataChanges:   dataChanges,
+			audit:         audit,
+			samples:       samples,
+			groupBy:       strings.ToLower(*groupBy),
+			flapping:      flapping,
+			rollout:       rollouts,
+			validate:      *validate,
+		}
+		sections = resources
+		if *tree {
+			sections = []string{"tree"}
+		} else if *summary {
+			sections = []string{"summary"}
+		} else if *onlyProblems {
+			sections = []string{"problems"}
+		} else if *fieldManagers {
+			sections = []string{"field-managers"}
+		} else if *diffNamespace != "" {
+			sections = []string{"namespace-diff"}
+		}
+		render := func(p *printer, src *source, section string) {
+			resource, cluster, _ := strings.Cut(section, "@")
+			if cluster != "" {
+				clusterSrc, sp := *clusters[cluster], *p
+				clusterSrc.section, sp.cluster = src.section, cluster
+				src, p = &clusterSrc, &sp
+			}
+			// several resource types share the screen: each is collapsed to
+			// its counts and unhealthy objects unless -expand names it
+			if len(sections) > 1 && format == "table" && !expand[section] && !expand[resource] {
+				printCollapsed(ctx, p, src, resource, *namespace, listOpts)
+			} else {
+				if len(sections) > 1 && format == "table" {
+					fmt.Fprintf(p.w, "\n▾ %s\n", section)
+				} else if len(sections) > 1 && format == "markdown" {
+					fmt.Fprintf(p.w, "\n### %s\n", section)
+				}
+				switch resource {
+				case "tree":
+					printTree(ctx, p, src, *namespace, *selector, *name)
+				case "summary":
+					printSummary(ctx, p, src, *namespace, listOpts)
+				case "namespace-diff":
+					printNamespaceDiff(ctx, p, src, resources[0], *namespace, *diffNamespace, listOpts)
+				case "field-managers":
+					printFieldManagers(ctx, p, src, resources[0], *namespace, *name)
+				case "problems":
+					problemTypes := summaryResources
+					flag.Visit(func(f *flag.Flag) {
+						if f.Name == "resource" {
+							problemTypes = resources
+						}
+					})
+					printProblems(ctx, p, src, *namespace, problemTypes, listOpts)
+				case "pods":
+					if *cleanupEvicted {
+						printEvictedPods(ctx, p, src, *namespace, listOpts)
+					} else if *showContainers {
+						listContainers(ctx, p, src, *namespace, listOpts)
+					} else if *showEnv {
+						listEnvVars(ctx, p, src, *namespace, listOpts)
+					} else {
+						listPods(ctx, p, src, *namespace, listOpts)
+					}
+				case "deployments":
+					if *showPods {
+						printDeploymentPods(ctx, p, src, *namespace, *name)
+					} else if *history {
+						printDeploymentHistory(ctx, p, src, *namespace, *name)
+					} else {
+						listDeployments(ctx, p, src, *namespace, listOpts)
+					}
+				case "services":
+					if *showEndpoints {
+						printServiceEndpoints(ctx, p, src, *namespace, *name)
+					} else if format == "mermaid" {
+						printServiceGraph(ctx, p.w, src, *namespace, listOpts)
+					} else {
+						listServices(ctx, p, src, *namespace, listOpts)
+					}
+				case "configmaps", "secrets":
+					if *usedBy {
+						printUsedBy(ctx, p, src, resource, *namespace, *name)
+					} else if resource == "configmaps" {
+						listConfigMaps(ctx, p, src, *namespace, listOpts)
+					} else {
+						listSecrets(ctx, p, src, *namespace, listOpts)
+					}
+				case "replicationcontrollers":
+					listReplicationControllers(ctx, p, src, *namespace, listOpts)
+				case "leases":
+					listLeases(ctx, p, src, *namespace, listOpts)
+				case "nodes":
+					listNodes(ctx, p, src, listOpts, *nodeConditions)
+				case "validatingwebhookconfigurations":
+					listValidatingWebhooks(ctx, p, src, listOpts)
+				case "mutatingwebhookconfigurations":
+					listMutatingWebhooks(ctx, p, src, listOpts)
+				case "priorityclasses":
+					listPriorityClasses(ctx, p, src, listOpts)
+				case "componentstatuses":
+					listComponentStatuses(ctx, p, src, listOpts)
+				case "gateways":
+					listGateways(ctx, p, src, *namespace, listOpts)
+				case "httproutes":
+					listHTTPRoutes(ctx, p, src, *namespace, listOpts)
+				case "volumeattachments":
+					listVolumeAttachments(ctx, p, src, listOpts)
+				case "csidrivers":
+					listCSIDrivers(ctx, p, src, listOpts)
+				case "csinodes":
+					listCSINodes(ctx, p, src, listOpts)
+				case "overview":
+					printOverview(ctx, p, src, listOpts)
+				case "pvc-pending":
+					printPendingVolumes(ctx, p, src, *namespace, listOpts)
+				case "images":
+					printImages(ctx, p, src, *imageFilter, listOpts)
+				default:
+					// anything else, CRDs included, can still be shown as custom
+					// columns or raw objects
+					if (format != "custom-columns" && format != "raw") || src.dynamic == nil {
+						fmt.Printf("Unsupported resource type: %s\n", resource)
+						if matches := closeMatches(resource, localResourceNames()); len(matches) > 0 {
+							fmt.Printf("Did you mean %s?\n", strings.Join(matches, ", "))
+						}
+						os.Exit(1)
+					}
+					listDynamic(ctx, p, src, resource, *namespace, listOpts)
+				}
+			}
+		}
+		failed = nil
+		if len(sections) > 1 && src.dump == nil {
+			// fetch the sections concurrently; the slowest List sets the
+			// tick's latency rather than the sum of them all
+			for i, errs := range renderSections(p, src, sections, render) {
+				if len(errs) > 0 {
+					failed = append(failed, sectionFailure{sections[i], errs[0]})
+				}
+			}
+		} else {
+			for _, resource := range sections {
+				reported := len(reportedErrors)
+				render(p, src, resource)
+				if len(reportedErrors) > reported {
+					failed = append(failed, sectionFailure{resource, reportedErrors[reported]})
+				}
+			}
+		}
+		if len(failed) > 0 && len(sections) > 1 {
+			printFailures(p, failed)
+		}
+		reportedErrors = nil
+
+		if err := closeOutput(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if redraw {
+			// clear whatever is left of a longer previous tick, then refresh
+			// the churn line now that this tick's lists are counted
+			fmt.Print("\033[J")
+			if src.churn != nil {
+				fmt.Printf("\0337\033[2;1H%s\033[K\0338", src.churn.header())
+			}
+			if keys != nil && keys.paused {
+				keys.printPaused()
+			}
+		}
+		if src.latency != nil {
+			src.latency.report(os.Stderr, *watch)
+		}
+		if audit != nil {
+			if err := audit.flush(); err != nil {
+				fmt.Printf("Error: writing %s: %v\n", *auditFile, err)
+				os.Exit(1)
+			}
+		}
+		if samples != nil {
+			if err := samples.flush(); err != nil {
+				fmt.Printf("Error: writing %s: %v\n", *sqlitePath, err)
+				os.Exit(1)
+			}
+		}
+		if src.notify != nil {
+			src.notify.flush()
+		}
+		if gateway != nil {
+			// a missed push is made up by the next one
+			if err := gateway.push(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: push to %s failed: %v\n", *pushGatewayURL, err)
+			}
+		}
+
+		if beat != nil && !redraw {
+			status := "connected"
+			if src.reconnect != nil {
+				status = src.reconnect.status()
+			}
+			if src.dump != nil {
+				status = "reading " + *fromFile
+			}
+			if len(failed) > 0 {
+				status = fmt.Sprintf("%d of %d types failed to list", len(failed), len(sections))
+			}
+			beat.beat(strings.Join(sections, ","), status)
+		}
 
 		// If watch mode is not enabled, break after the first iteration
 		if !*watch {
 			break
 		}
 
-		// Clear the screen for watch mode
-		fmt.Print("\033[H\033[2J")
-		fmt.Printf("Watching %s in namespace %s (Ctrl+C to exit)...\n", *resourceType, *namespace)
+		// Stop once the requested number of snapshots has been taken
+		if *watchCount > 0 && iteration >= *watchCount {
+			break
+		}
+
+		// Sleep for the specified interval, stretched while nothing changes
+		// in adaptive mode
+		if backoff != nil {
+			sleep = backoff.next(src.changes.commit())
+		}
+		if redraw && keys == nil && iteration == 1 {
+			if keys, err = startKeyboard(stop); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			if keys != nil {
+				defer keys.restore()
+			}
+		}
+		if keys != nil {
+			keys.wait(ctx, sleep, stop)
+		} else {
+			select {
+			case <-time.After(sleep):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if keys != nil {
+		keys.restore()
+	}
+	if flapping != nil {
+		flapping.printSummary(os.Stdout)
+	}
+	if scheduling != nil {
+		scheduling.printSummary(os.Stdout)
+	}
+	if imagePulls != nil {
+		imagePulls.printSummary(os.Stdout)
+	}
+
+	if audit != nil {
+		if err := audit.close(); err != nil {
+			fmt.Printf("Error: writing %s: %v\n", *auditFile, err)
+			os.Exit(1)
+		}
+	}
+	if samples != nil {
+		if err := samples.close(); err != nil {
+			fmt.Printf("Error: writing %s: %v\n", *sqlitePath, err)
+			os.Exit(1)
+		}
+	}
+
+	if err := profiles.stop(); err != nil {
+		fmt.Printf("Error: writing profile: %v\n", err)
+		os.Exit(1)
+	}
 
-		// Sleep for the specified interval
-		time.Sleep(time.Duration(*interval) * time.Second)
+	// a single run that could list only some of the types is a partial
+	// failure
+	if !*watch && len(failed) == len(sections) && len(failed) > 0 {
+		os.Exit(1)
+	}
+	if !*watch && len(failed) > 0 {
+		os.Exit(3)
 	}
 }
 
-func listPods(ctx context.Context, clientset *kubernetes.Clientset, namespace string) {
-	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+// openOutput returns the writer for one rendering pass: stdout, or path
+// truncated (or appended to) so each watch tick lands in the file.
+func openOutput(path string, appendMode bool) (io.Writer, func() error, error) {
+	if path == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendMode {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Close, nil
+}
+
+// source is where resources are read from: the live cluster, or a dump
+// loaded with --from-file.
+type source struct {
+	clientset   *kubernetes.Clientset
+	dynamic     dynamic.Interface
+	mapper      meta.RESTMapper
+	dump        dump
+	latency     *latencyStats       // set with -show-latency
+	changes     *changeTracker      // set with -adaptive
+	health      *healthStatus       // set with -health-addr
+	churn       *churnCounter       // set in watch mode
+	reconnect   *reconnector        // set in watch mode
+	credentials *credentialReloader // set in watch mode, for the main cluster
+	notify      *problemNotifier    // set with -notify in watch mode
+	section     *sectionRun         // set while several sections render concurrently
+	cluster     string              // the CLUSTER of a -resource TYPE@CLUSTER source
+}
+
+// configureClient applies -proxy-url, the -as impersonation flags and the
+// -timeout request deadline to config.
+func configureClient(config *rest.Config, proxyURL, asUser, asUID, asGroups string, timeout time.Duration) error {
+	// Without -proxy-url client-go falls back to HTTPS_PROXY/NO_PROXY,
+	// or the kubeconfig's proxy-url
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("invalid -proxy-url %q", proxyURL)
+		}
+		config.Proxy = http.ProxyURL(u)
+	}
+
+	if asUser != "" {
+		config.Impersonate = rest.ImpersonationConfig{
+			UserName: asUser,
+			UID:      asUID,
+			Groups:   splitList(asGroups),
+		}
+	} else if asGroups != "" || asUID != "" {
+		return fmt.Errorf("-as-group and -as-uid need -as")
+	}
+
+	if timeout > 0 {
+		config.Wrap(func(next http.RoundTripper) http.RoundTripper {
+			return &timeoutTransport{next: next, timeout: timeout}
+		})
+	}
+	return nil
+}
+
+// connect creates src's clients for the cluster config points at.
+func (src *source) connect(config *rest.Config) error {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	src.clientset = clientset
+	src.dynamic, err = dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	// the shortcut expander resolves the server's short names, CRDs'
+	// included, that the registry does not know
+	discovery := memory.NewMemCacheClient(clientset.Discovery())
+	src.mapper = restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(discovery), discovery, func(warning string) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	})
+	return nil
+}
+
+// fetch returns the live list, or fills empty from the dump when one is
+// loaded so the table logic below never needs to know the difference.
+func fetch[L runtime.Object](src *source, empty L, namespace string, opts metav1.ListOptions, live func() (L, error)) (L, error) {
+	if src.dump != nil {
+		return empty, src.dump.into(empty, namespace, opts)
+	}
+
+	if src.section != nil {
+		// let the other sections render while this one waits on the API
+		src.section.release()
+	}
+	start := time.Now()
+	var list L
+	var err error
+	if src.reconnect != nil {
+		list, err = retry(src.reconnect, src.credentials, live)
+	} else {
+		list, err = live()
+	}
+	elapsed := time.Since(start)
+	if src.section != nil {
+		src.section.acquire()
+	}
+	if src.latency != nil {
+		src.latency.record(strings.TrimSuffix(reflect.TypeOf(empty).Elem().Name(), "List"), elapsed)
+	}
+	if src.changes != nil && err == nil {
+		src.changes.add(list)
+	}
+	if src.health != nil && err == nil {
+		src.health.ready.Store(true)
+	}
+	if src.churn != nil && err == nil {
+		src.churn.observe(atCluster(reflect.TypeOf(empty).Elem().Name(), src.cluster), namespace, opts, list)
+	}
+	if src.notify != nil && err == nil {
+		src.notify.observe(src.cluster, list)
+	}
+	return list, err
+}
+
+var podColumns = []column[PodRow]{
+	{"NAME", 40, func(r PodRow) string { return r.Name }, nil},
+	{"STATUS", 20, func(r PodRow) string { return r.Status }, func(r PodRow) string { return withoutDetail(r.Status) }},
+	{"READY", 15, func(r PodRow) string { return r.Ready }, nil},
+	{"RESTARTS", 10, func(r PodRow) string { return strconv.Itoa(r.Restarts) }, nil},
+	{"AGE", 10, func(r PodRow) string { return r.Age }, ignored[PodRow]},
+}
+
+// podRestartRateColumn follows RESTARTS in watch mode.
+var podRestartRateColumn = column[PodRow]{"RESTARTS/MIN", 13, func(r PodRow) string {
+	if r.RestartsPerMinute == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*r.RestartsPerMinute, 'f', 1, 64)
+}, nil}
+
+// podWideColumns are added by -wide.
+var podWideColumns = []column[PodRow]{
+	{"IP", 16, func(r PodRow) string { return orNone(r.IP) }, nil},
+	{"NODE", 30, func(r PodRow) string { return orNone(r.Node) }, nil},
+	{"QOS", 12, func(r PodRow) string { return r.QOSClass }, nil},
+	{"ZONE", 15, func(r PodRow) string { return orNone(r.Zone) }, nil},
+}
+
+func listPods(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	pods, err := fetch(src, &corev1.PodList{}, namespace, opts, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	})
 	if err != nil {
 		handleError(err)
 		return
 	}
+	if p.statuses != nil {
+		var selected []corev1.Pod
+		for _, pod := range pods.Items {
+			if podHasStatus(pod, p.statuses) {
+				selected = append(selected, pod)
+			}
+		}
+		pods.Items = selected
+	}
+
+	columns := podColumns
+	var rates map[string]float64
+	if p.restartRate != nil {
+		columns = append(append(columns[:4:4], podRestartRateColumn), columns[4:]...)
+		rates = p.restartRate.observe(p.cluster, pods.Items)
+	}
+	if p.wide || p.columnFields("pods") != nil || p.groupBy != "" {
+		columns = append(columns[:len(columns):len(columns)], podWideColumns...)
+	}
+
+	var warnings map[string]string
+	if p.explain {
+		columns = append(columns[:len(columns):len(columns)], podExplainColumn)
+		if warnings, err = podWarnings(ctx, src, namespace); err != nil {
+			handleError(err)
+			return
+		}
+	}
+
+	// the zone is its node's, which takes listing the nodes
+	var zones map[string]string
+	if p.wide || p.columnFields("pods") != nil || p.groupBy != "" {
+		zones = nodeZones(ctx, src)
+	}
 
-	fmt.Printf("\n%-40s %-20s %-15s %-10s %-10s\n", "NAME", "STATUS", "READY", "RESTARTS", "AGE")
+	var rows []PodRow
+	evicted := 0
 	for _, pod := range pods.Items {
-		containerReady := fmt.Sprintf("%d/%d", getReadyContainers(pod.Status.ContainerStatuses), len(pod.Spec.Containers))
-		age := formatAge(pod.CreationTimestamp.Time)
-		restarts := getTotalRestarts(pod.Status.ContainerStatuses)
+		if isEvicted(pod) {
+			evicted++
+		}
+		row := newPodRow(pod)
+		row.Zone = zones[pod.Spec.NodeName]
+		if rate, ok := rates[pod.Namespace+"/"+pod.Name]; ok {
+			row.RestartsPerMinute = &rate
+		}
+		if p.explain {
+			row.Explanation = explainPod(pod, warnings[pod.Namespace+"/"+pod.Name])
+		}
+		rows = append(rows, row)
+	}
+	printRows(p, "pods", pods, columns, rows)
+	if evicted > 0 && p.format == "table" && p.onChange == nil {
+		fmt.Fprintf(p.w, "Evicted pods: %d (-cleanup-evicted lists them for deletion)\n", evicted)
+	}
 
-		fmt.Printf("%-40s %-20s %-15s %-10d %-10s\n",
-			pod.Name,
-			string(pod.Status.Phase),
-			containerReady,
-			restarts,
-			age)
+	// keep machine-readable output clean
+	w := p.w
+	if p.format != "table" {
+		w = os.Stderr
+	}
+	if p.restartSpikes != nil {
+		p.restartSpikes.observe(w, p.cluster, pods.Items)
 	}
+	if p.scheduling != nil {
+		p.scheduling.observe(w, p.cluster, pods.Items)
+	}
+	if p.readiness != nil {
+		p.readiness.observe(w, p.cluster, pods.Items)
+	}
+	if p.imagePulls != nil {
+		if err := p.imagePulls.observe(ctx, w, src, namespace, pods.Items); err != nil {
+			handleError(err)
+		}
+	}
+}
 
-	fmt.Printf("\nTotal pods: %d\n", len(pods.Items))
+var deploymentColumns = []column[DeploymentRow]{
+	{"NAME", 40, func(r DeploymentRow) string { return r.Name }, nil},
+	{"READY", 10, func(r DeploymentRow) string { return r.Ready }, nil},
+	{"UP-TO-DATE", 10, func(r DeploymentRow) string { return strconv.Itoa(int(r.UpToDate)) }, nil},
+	{"AVAILABLE", 10, func(r DeploymentRow) string { return strconv.Itoa(int(r.Available)) }, nil},
+	{"AGE", 10, func(r DeploymentRow) string { return r.Age }, ignored[DeploymentRow]},
 }
 
-func listDeployments(ctx context.Context, clientset *kubernetes.Clientset, namespace string) {
-	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+// deploymentWideColumns are added by -wide.
+var deploymentWideColumns = []column[DeploymentRow]{
+	{"CHANGE-CAUSE", 0, func(r DeploymentRow) string { return orNone(r.ChangeCause) }, nil},
+}
+
+func listDeployments(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	deployments, err := fetch(src, &appsv1.DeploymentList{}, namespace, opts, func() (*appsv1.DeploymentList, error) {
+		return src.clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+	})
 	if err != nil {
 		handleError(err)
 		return
 	}
 
-	fmt.Printf("\n%-40s %-10s %-10s %-10s %-10s\n", "NAME", "READY", "UP-TO-DATE", "AVAILABLE", "AGE")
-	for _, deployment := range deployments.Items {
-		ready := fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, *deployment.Spec.Replicas)
-		age := formatAge(deployment.CreationTimestamp.Time)
+	columns := deploymentColumns
+	if p.rollout != nil {
+		columns = deploymentRolloutColumns
+	} else if p.wide {
+		columns = append(columns[:len(columns):len(columns)], deploymentWideColumns...)
+	}
 
-		fmt.Printf("%-40s %-10s %-10d %-10d %-10s\n",
-			deployment.Name,
-			ready,
-			deployment.Status.UpdatedReplicas,
-			deployment.Status.AvailableReplicas,
-			age)
+	var rows []DeploymentRow
+	for _, deployment := range deployments.Items {
+		row := newDeploymentRow(deployment)
+		if p.rollout != nil {
+			row.Rollout = p.rollout.observe(deployment)
+		}
+		rows = append(rows, row)
 	}
+	printRows(p, "deployments", deployments, columns, rows)
+}
 
-	fmt.Printf("\nTotal deployments: %d\n", len(deployments.Items))
+var serviceColumns = []column[ServiceRow]{
+	{"NAME", 40, func(r ServiceRow) string { return r.Name }, nil},
+	{"TYPE", 20, func(r ServiceRow) string { return r.Type }, nil},
+	{"CLUSTER-IP", 20, func(r ServiceRow) string { return r.ClusterIP }, nil},
+	{"EXTERNAL-IP", 15, func(r ServiceRow) string { return r.ExternalIP }, nil},
+	{"PORTS", 30, func(r ServiceRow) string { return joinOrNone(r.Ports) }, nil},
+	{"AGE", 10, func(r ServiceRow) string { return r.Age }, ignored[ServiceRow]},
 }
 
-func listServices(ctx context.Context, clientset *kubernetes.Clientset, namespace string) {
-	services, err := clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+func listServices(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	services, err := fetch(src, &corev1.ServiceList{}, namespace, opts, func() (*corev1.ServiceList, error) {
+		return src.clientset.CoreV1().Services(namespace).List(ctx, opts)
+	})
 	if err != nil {
 		handleError(err)
 		return
 	}
 
-	fmt.Printf("\n%-40s %-20s %-20s %-15s %-10s\n", "NAME", "TYPE", "CLUSTER-IP", "EXTERNAL-IP", "AGE")
+	var rows []ServiceRow
 	for _, svc := range services.Items {
-		externalIP := "<none>"
-		if len(svc.Status.LoadBalancer.Ingress) > 0 {
-			externalIP = svc.Status.LoadBalancer.Ingress[0].IP
-			if externalIP == "" && svc.Status.LoadBalancer.Ingress[0].Hostname != "" {
-				externalIP = svc.Status.LoadBalancer.Ingress[0].Hostname
-			}
-		}
-
-		age := formatAge(svc.CreationTimestamp.Time)
+		rows = append(rows, newServiceRow(svc))
+	}
+	printRows(p, "services", services, serviceColumns, rows)
 
-		fmt.Printf("%-40s %-20s %-20s %-15s %-10s\n",
-			svc.Name,
-			string(svc.Spec.Type),
-			svc.Spec.ClusterIP,
-			externalIP,
-			age)
+	if p.validate {
+		warnings, err := validateServices(ctx, src, namespace)
+		if err != nil {
+			handleError(err)
+			return
+		}
+		// keep machine-readable output clean
+		w := p.w
+		if p.format != "table" {
+			w = os.Stderr
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(w, "Warning: %s\n", warning)
+		}
 	}
+}
 
-	fmt.Printf("\nTotal services: %d\n", len(services.Items))
+var configMapColumns = []column[ConfigMapRow]{
+	{"NAME", 40, func(r ConfigMapRow) string { return r.Name }, nil},
+	{"DATA", 15, func(r ConfigMapRow) string { return strconv.Itoa(r.Data) }, nil},
+	{"AGE", 10, func(r ConfigMapRow) string { return r.Age }, ignored[ConfigMapRow]},
 }
 
-func listConfigMaps(ctx context.Context, clientset *kubernetes.Clientset, namespace string) {
-	configMaps, err := clientset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+func listConfigMaps(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	configMaps, err := fetch(src, &corev1.ConfigMapList{}, namespace, opts, func() (*corev1.ConfigMapList, error) {
+		return src.clientset.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+	})
 	if err != nil {
 		handleError(err)
 		return
 	}
 
-	fmt.Printf("\n%-40s %-15s %-10s\n", "NAME", "DATA", "AGE")
+	var rows []ConfigMapRow
 	for _, cm := range configMaps.Items {
-		age := formatAge(cm.CreationTimestamp.Time)
+		rows = append(rows, newConfigMapRow(cm))
+	}
+	printRows(p, "configmaps", configMaps, configMapColumns, rows)
 
-		fmt.Printf("%-40s %-15d %-10s\n",
-			cm.Name,
-			len(cm.Data),
-			age)
+	if p.dataChanges != nil {
+		w := p.w
+		if p.format != "table" {
+			w = os.Stderr
+		}
+		p.dataChanges.observeConfigMaps(w, p.cluster, configMaps.Items)
 	}
+}
 
-	fmt.Printf("\nTotal configmaps: %d\n", len(configMaps.Items))
+var secretColumns = []column[SecretRow]{
+	{"NAME", 40, func(r SecretRow) string { return r.Name }, nil},
+	{"TYPE", 15, func(r SecretRow) string { return r.Type }, nil},
+	{"DATA", 15, func(r SecretRow) string { return strconv.Itoa(r.Data) }, nil},
+	{"AGE", 10, func(r SecretRow) string { return r.Age }, ignored[SecretRow]},
 }
 
-func listSecrets(ctx context.Context, clientset *kubernetes.Clientset, namespace string) {
-	secrets, err := clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+func listSecrets(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	secrets, err := fetch(src, &corev1.SecretList{}, namespace, opts, func() (*corev1.SecretList, error) {
+		return src.clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+	})
 	if err != nil {
 		handleError(err)
 		return
 	}
 
-	fmt.Printf("\n%-40s %-15s %-15s %-10s\n", "NAME", "TYPE", "DATA", "AGE")
+	var rows []SecretRow
 	for _, secret := range secrets.Items {
-		age := formatAge(secret.CreationTimestamp.Time)
+		rows = append(rows, newSecretRow(secret))
+	}
+	printRows(p, "secrets", secrets, secretColumns, rows)
 
-		fmt.Printf("%-40s %-15s %-15d %-10s\n",
-			secret.Name,
-			string(secret.Type),
-			len(secret.Data),
-			age)
+	if p.dataChanges != nil {
+		w := p.w
+		if p.format != "table" {
+			w = os.Stderr
+		}
+		p.dataChanges.observeSecrets(w, p.cluster, secrets.Items)
 	}
+}
 
-	fmt.Printf("\nTotal secrets: %d\n", len(secrets.Items))
+var nodeColumns = []column[NodeRow]{
+	{"NAME", 40, func(r NodeRow) string { return r.Name }, nil},
+	{"STATUS", 15, func(r NodeRow) string { return r.Status }, nil},
+	{"ROLES", 15, func(r NodeRow) string { return r.Roles }, nil},
+	{"VERSION", 20, func(r NodeRow) string { return r.Version }, nil},
+	{"ZONE", 15, func(r NodeRow) string { return orNone(r.Zone) }, nil},
+	{"AGE", 10, func(r NodeRow) string { return r.Age }, ignored[NodeRow]},
 }
 
-func listNodes(ctx context.Context, clientset *kubernetes.Clientset) {
-	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+var nodeConditionsColumn = column[NodeRow]{"CONDITIONS", 0, func(r NodeRow) string { return joinOrNone(r.Conditions) }, nil}
+
+func listNodes(ctx context.Context, p *printer, src *source, opts metav1.ListOptions, showConditions bool) {
+	nodes, err := fetch(src, &corev1.NodeList{}, "", opts, func() (*corev1.NodeList, error) {
+		return src.clientset.CoreV1().Nodes().List(ctx, opts)
+	})
 	if err != nil {
 		handleError(err)
 		return
 	}
 
-	fmt.Printf("\n%-40s %-15s %-15s %-20s %-10s\n", "NAME", "STATUS", "ROLES", "VERSION", "AGE")
+	columns := nodeColumns
+	if showConditions || p.columnFields("nodes") != nil {
+		columns = append(columns[:len(columns):len(columns)], nodeConditionsColumn)
+	}
+
+	var rows []NodeRow
 	for _, node := range nodes.Items {
-		status := "Ready"
-		for _, condition := range node.Status.Conditions {
-			if condition.Type == "Ready" {
-				if condition.Status != "True" {
-					status = "NotReady"
-				}
-				break
-			}
-		}
+		rows = append(rows, newNodeRow(node))
+	}
+	printRows(p, "nodes", nodes, columns, rows)
 
-		roles := "<none>"
-		if val, ok := node.Labels["kubernetes.io/role"]; ok {
-			roles = val
-		} else if val, ok := node.Labels["node-role.kubernetes.io/master"]; ok && val == "true" {
-			roles = "master"
-		} else if val, ok := node.Labels["node-role.kubernetes.io/control-plane"]; ok && val == "true" {
-			roles = "control-plane"
+	if p.nodeAlerts != nil {
+		w := p.w
+		if p.format != "table" {
+			w = os.Stderr
 		}
-
-		version := node.Status.NodeInfo.KubeletVersion
-		age := formatAge(node.CreationTimestamp.Time)
-
-		fmt.Printf("%-40s %-15s %-15s %-20s %-10s\n",
-			node.Name,
-			status,
-			roles,
-			version,
-			age)
+		p.nodeAlerts.observe(ctx, w, src, p.cluster, nodes.Items)
 	}
-
-	fmt.Printf("\nTotal nodes: %d\n", len(nodes.Items))
 }
 
 // Helper functions
@@ -249,6 +1415,78 @@ func getReadyContainers(statuses []corev1.ContainerStatus) int {
 	return ready
 }
 
+// getPodStatus is the pod phase, except that a deleted pod still shutting
+// down is "Terminating" with how long it has been, and a running pod with
+// a container whose startup probe has not passed yet is "Starting": it is
+// slow to start, not crashing.
+func getPodStatus(pod corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return fmt.Sprintf("Terminating (%s)", formatAge(terminatingSince(pod)))
+	}
+	if isEvicted(pod) {
+		return "Evicted"
+	}
+	if pod.Status.Phase == corev1.PodRunning {
+		for _, c := range pod.Spec.Containers {
+			for _, status := range pod.Status.ContainerStatuses {
+				if status.Name == c.Name && awaitingStartupProbe(c, status) {
+					return "Starting"
+				}
+			}
+		}
+		if _, notReady := notReadySince(pod); notReady {
+			return "NotReady"
+		}
+	}
+	return string(pod.Status.Phase)
+}
+
+// notReadySince reports whether a Running pod is not ready, and since
+// when: its Ready condition's last transition, or its start without one.
+// Such a pod is left out of its services' endpoints.
+func notReadySince(pod corev1.Pod) (time.Time, bool) {
+	if pod.Status.Phase != corev1.PodRunning {
+		return time.Time{}, false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.LastTransitionTime.Time, condition.Status != corev1.ConditionTrue
+		}
+	}
+	var since time.Time
+	if pod.Status.StartTime != nil {
+		since = pod.Status.StartTime.Time
+	}
+	return since, getReadyContainers(pod.Status.ContainerStatuses) < len(pod.Spec.Containers)
+}
+
+// notReadyContainers names the pod's containers that are not ready.
+func notReadyContainers(pod corev1.Pod) []string {
+	var names []string
+	for _, status := range pod.Status.ContainerStatuses {
+		if !status.Ready {
+			names = append(names, status.Name)
+		}
+	}
+	return names
+}
+
+// terminatingSince is when the pod was deleted. The API server sets
+// DeletionTimestamp to the end of the grace period, not to the deletion.
+func terminatingSince(pod corev1.Pod) time.Time {
+	since := pod.DeletionTimestamp.Time
+	if pod.DeletionGracePeriodSeconds != nil {
+		since = since.Add(-time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second)
+	}
+	return since
+}
+
+// awaitingStartupProbe reports whether c is running but its startup probe
+// has not succeeded yet.
+func awaitingStartupProbe(c corev1.Container, status corev1.ContainerStatus) bool {
+	return c.StartupProbe != nil && status.State.Running != nil && (status.Started == nil || !*status.Started)
+}
+
 func getTotalRestarts(statuses []corev1.ContainerStatus) int {
 	restarts := 0
 	for _, status := range statuses {
@@ -257,6 +1495,126 @@ func getTotalRestarts(statuses []corev1.ContainerStatus) int {
 	return restarts
 }
 
+func getNodeStatus(node corev1.Node) string {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == "Ready" {
+			if condition.Status != "True" {
+				return "NotReady"
+			}
+			break
+		}
+	}
+	return "Ready"
+}
+
+// nodeProblemConditions are the node conditions that indicate trouble when True.
+var nodeProblemConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+	corev1.NodeNetworkUnavailable,
+}
+
+func getNodeProblems(conditions []corev1.NodeCondition) []string {
+	var problems []string
+	for _, problem := range nodeProblemConditions {
+		for _, condition := range conditions {
+			if condition.Type == problem && condition.Status == corev1.ConditionTrue {
+				problems = append(problems, string(problem))
+			}
+		}
+	}
+	return problems
+}
+
+// splitList splits a comma-separated flag value, returning nil when empty.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ",")
+}
+
+// getQOSClass returns the pod's QoS class, computing it from requests and
+// limits the way the kubelet does when the status predates the field.
+func getQOSClass(pod corev1.Pod) corev1.PodQOSClass {
+	if pod.Status.QOSClass != "" {
+		return pod.Status.QOSClass
+	}
+
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+	guaranteed := true
+	containers := append(pod.Spec.InitContainers[:len(pod.Spec.InitContainers):len(pod.Spec.InitContainers)], pod.Spec.Containers...)
+	for _, container := range containers {
+		// requests default to limits when only limits are set
+		effective := corev1.ResourceList{}
+		for name, quantity := range container.Resources.Limits {
+			effective[name] = quantity
+		}
+		for name, quantity := range container.Resources.Requests {
+			effective[name] = quantity
+		}
+		for name, quantity := range effective {
+			if isQOSResource(name) && !quantity.IsZero() {
+				total := requests[name]
+				total.Add(quantity)
+				requests[name] = total
+			}
+		}
+
+		found := map[corev1.ResourceName]bool{}
+		for name, quantity := range container.Resources.Limits {
+			if isQOSResource(name) && !quantity.IsZero() {
+				found[name] = true
+				total := limits[name]
+				total.Add(quantity)
+				limits[name] = total
+			}
+		}
+		if !found[corev1.ResourceCPU] || !found[corev1.ResourceMemory] {
+			guaranteed = false
+		}
+	}
+
+	if len(requests) == 0 && len(limits) == 0 {
+		return corev1.PodQOSBestEffort
+	}
+	if guaranteed {
+		for name, request := range requests {
+			if limit, ok := limits[name]; !ok || limit.Cmp(request) != 0 {
+				guaranteed = false
+				break
+			}
+		}
+	}
+	if guaranteed && len(requests) == len(limits) {
+		return corev1.PodQOSGuaranteed
+	}
+	return corev1.PodQOSBurstable
+}
+
+func isQOSResource(name corev1.ResourceName) bool {
+	return name == corev1.ResourceCPU || name == corev1.ResourceMemory
+}
+
 func formatAge(t time.Time) string {
 	duration := time.Since(t)
 	if duration.Hours() > 24 {
@@ -272,9 +1630,14 @@ func formatAge(t time.Time) string {
 }
 
 func handleError(err error) {
+	reportedErrors = append(reportedErrors, err)
 	if statusError, isStatus := err.(*errors.StatusError); isStatus {
-		fmt.Printf("Error: %v\n", statusError.ErrStatus.Message)
+		message := statusError.ErrStatus.Message
+		if errors.IsForbidden(err) && strings.Contains(message, "cannot impersonate") {
+			message += " (impersonating with -as needs RBAC for the impersonate verb)"
+		}
+		fmt.Fprintf(errorOutput, "Error: %v\n", message)
 	} else {
-		fmt.Printf("Error: %v\n", err)
+		fmt.Fprintf(errorOutput, "Error: %v\n", err)
 	}
 }
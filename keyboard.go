@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// keyboard reads single key presses during a watch: p pauses refreshing,
+// space refreshes once and r resumes. The terminal is in cbreak mode while
+// it runs (see makeCbreak).
+type keyboard struct {
+	keys    chan byte
+	reset   func() error
+	paused  bool
+	stopped bool
+}
+
+// startKeyboard puts the terminal into cbreak mode, or returns nil when
+// stdin is not a terminal. A Ctrl+C read as a key, where the terminal has
+// no cbreak mode, calls cancel right away, as a retry backing off during
+// an outage is not waiting for keys.
+func startKeyboard(cancel func()) (*keyboard, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, nil
+	}
+	reset, err := makeCbreak(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &keyboard{keys: make(chan byte), reset: reset}
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if n, err := os.Stdin.Read(buf); err != nil || n == 0 {
+				return
+			}
+			if buf[0] == 3 { // Ctrl+C
+				cancel()
+				continue
+			}
+			k.keys <- buf[0]
+		}
+	}()
+	return k, nil
+}
+
+// restore returns the terminal to the mode it was in before.
+func (k *keyboard) restore() {
+	if !k.stopped {
+		k.stopped = true
+		k.reset()
+	}
+}
+
+// wait sleeps until the next tick is due, ctx is done or a key asks for a
+// refresh. While paused only space or r end the wait. cancel is called on
+// q.
+func (k *keyboard) wait(ctx context.Context, d time.Duration, cancel func()) {
+	tick := time.After(d)
+	if k.paused {
+		tick = nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+			return
+		case key := <-k.keys:
+			switch key {
+			case 'p':
+				if !k.paused {
+					k.paused = true
+					tick = nil
+					k.printPaused()
+				}
+			case 'r':
+				if k.paused {
+					k.paused = false
+					return
+				}
+			case ' ':
+				return
+			case 'q':
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func (k *keyboard) printPaused() {
+	fmt.Print("\r\033[KPaused: space refreshes once, r resumes\r\n")
+}
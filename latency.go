@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// latencyWindow is how many recent List calls the rolling average covers.
+const latencyWindow = 20
+
+// latencyStats records how long List calls take, for -show-latency.
+type latencyStats struct {
+	tick   []string        // "Pod 12ms" for each call since the last report
+	recent []time.Duration // the last latencyWindow calls
+}
+
+func (l *latencyStats) record(kind string, d time.Duration) {
+	l.tick = append(l.tick, fmt.Sprintf("%s %s", kind, d.Round(time.Millisecond)))
+	l.recent = append(l.recent, d)
+	if len(l.recent) > latencyWindow {
+		l.recent = l.recent[1:]
+	}
+}
+
+// report writes the calls made since the last report and, once there is
+// history to average, the rolling average.
+func (l *latencyStats) report(w io.Writer, watching bool) {
+	if len(l.tick) == 0 {
+		return
+	}
+
+	line := "API latency: " + strings.Join(l.tick, ", ")
+	if watching {
+		var total time.Duration
+		for _, d := range l.recent {
+			total += d
+		}
+		avg := total / time.Duration(len(l.recent))
+		line += fmt.Sprintf(" (rolling avg %s over last %d calls)", avg.Round(time.Millisecond), len(l.recent))
+	}
+	fmt.Fprintln(w, line)
+	l.tick = nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var leaseColumns = []column[LeaseRow]{
+	{"NAME", 40, func(r LeaseRow) string { return r.Name }, nil},
+	{"HOLDER", 45, func(r LeaseRow) string { return orNone(r.Holder) }, nil},
+	{"RENEW-TIME", 20, func(r LeaseRow) string {
+		if r.RenewTime.IsZero() {
+			return "<never>"
+		}
+		renewed := formatAge(r.RenewTime) + " ago"
+		if r.Stale {
+			renewed += " (stale)"
+		}
+		return renewed
+	}, func(r LeaseRow) string {
+		return fmt.Sprintf("%s stale=%t", r.RenewTime.Format(time.RFC3339), r.Stale)
+	}},
+	{"AGE", 10, func(r LeaseRow) string { return r.Age }, ignored[LeaseRow]},
+}
+
+// listLeases lists the Leases controllers use for leader election. A lease
+// not renewed within its duration is stale: its holder has stopped
+// renewing and nobody has taken over.
+func listLeases(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	leases, err := fetch(src, &coordinationv1.LeaseList{}, namespace, opts, func() (*coordinationv1.LeaseList, error) {
+		return src.clientset.CoordinationV1().Leases(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []LeaseRow
+	for _, lease := range leases.Items {
+		rows = append(rows, newLeaseRow(lease))
+	}
+	printRows(p, "leases", leases, leaseColumns, rows)
+}
+
+// leaseStale reports whether a held lease has gone unrenewed for longer
+// than its duration.
+func leaseStale(lease coordinationv1.Lease) bool {
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" || lease.Spec.RenewTime == nil {
+		return false
+	}
+	duration := 15 * time.Second
+	if lease.Spec.LeaseDurationSeconds != nil {
+		duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	return time.Since(lease.Spec.RenewTime.Time) > duration
+}
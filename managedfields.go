@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// printFieldManagers shows which field managers own which fields of the
+// named object, from its metadata.managedFields. A field owned by more
+// than one manager is marked with the others, which is where two
+// controllers fighting over a field show up.
+func printFieldManagers(ctx context.Context, p *printer, src *source, resourceType, namespace, name string) {
+	obj, err := getObject(ctx, src, resourceType, namespace, name)
+	if err != nil {
+		handleError(err)
+		return
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []FieldManagerRow
+	owners := map[string][]string{}
+	for _, entry := range accessor.GetManagedFields() {
+		row := FieldManagerRow{Manager: entry.Manager, Operation: string(entry.Operation), Subresource: entry.Subresource}
+		if entry.Time != nil {
+			row.Time = entry.Time.Time
+		}
+		if entry.FieldsV1 != nil {
+			var fields map[string]any
+			if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+				handleError(fmt.Errorf("managedFields of %s: %v", entry.Manager, err))
+				return
+			}
+			row.Fields = fieldPaths("", fields)
+			sort.Strings(row.Fields)
+		}
+		for _, field := range row.Fields {
+			owners[field] = append(owners[field], row.Manager)
+		}
+		rows = append(rows, row)
+	}
+
+	switch p.format {
+	case "json":
+		if rows == nil {
+			rows = []FieldManagerRow{}
+		}
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			handleError(err)
+			return
+		}
+		fmt.Fprintln(p.w, string(data))
+		return
+	case "table":
+	default:
+		fmt.Printf("Error: -o %s is not supported with -field-managers\n", p.format)
+		os.Exit(1)
+	}
+
+	object := strings.TrimSuffix(resourceType, "s") + " " + strings.TrimPrefix(accessor.GetNamespace()+"/"+accessor.GetName(), "/")
+	if len(rows) == 0 {
+		fmt.Fprintf(p.w, "\n%s has no managedFields\n", object)
+		return
+	}
+	fmt.Fprintf(p.w, "\nField managers of %s:\n", object)
+	for _, row := range rows {
+		operation := row.Operation
+		if row.Subresource != "" {
+			operation += " " + row.Subresource
+		}
+		updated := ""
+		if !row.Time.IsZero() {
+			updated = ", " + formatAge(row.Time) + " ago"
+		}
+		fmt.Fprintf(p.w, "\n%s (%s%s)\n", row.Manager, operation, updated)
+		for _, field := range row.Fields {
+			var others []string
+			for _, owner := range owners[field] {
+				if owner != row.Manager {
+					others = append(others, owner)
+				}
+			}
+			if len(others) > 0 {
+				fmt.Fprintf(p.w, "  → %-50s also: %s\n", field, strings.Join(others, ", "))
+			} else {
+				fmt.Fprintf(p.w, "  → %s\n", field)
+			}
+		}
+	}
+}
+
+// fieldPaths flattens a FieldsV1 set into readable paths such as
+// spec.template.spec.containers[name=app].image. Keys are "f:" fields,
+// "k:" list items by their keys, "v:" set values and "i:" list indexes;
+// "." marks a node that is owned itself as well as its children.
+func fieldPaths(prefix string, fields map[string]any) []string {
+	var paths []string
+	for key, value := range fields {
+		if key == "." {
+			paths = append(paths, prefix)
+			continue
+		}
+		path := prefix
+		kind, rest, _ := strings.Cut(key, ":")
+		switch kind {
+		case "f":
+			path = strings.TrimPrefix(prefix+"."+rest, ".")
+		case "k":
+			var item map[string]any
+			json.Unmarshal([]byte(rest), &item)
+			var pairs []string
+			for _, k := range sortedKeys(item) {
+				pairs = append(pairs, fmt.Sprintf("%s=%v", k, item[k]))
+			}
+			path += "[" + strings.Join(pairs, ",") + "]"
+		case "v":
+			var v any
+			json.Unmarshal([]byte(rest), &v)
+			path += fmt.Sprintf("[=%v]", v)
+		case "i":
+			path += "[" + rest + "]"
+		default:
+			path = strings.TrimPrefix(prefix+"."+key, ".")
+		}
+
+		children, _ := value.(map[string]any)
+		if len(children) == 0 {
+			paths = append(paths, path)
+			continue
+		}
+		paths = append(paths, fieldPaths(path, children)...)
+	}
+	return paths
+}
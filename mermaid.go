@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// mermaidUnsafe matches what cannot appear in a Mermaid node ID.
+var mermaidUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// printServiceGraph writes, for -o mermaid, a Mermaid flowchart linking
+// each service to the pods behind it and each pod to its node. The pods
+// are resolved as -endpoints does, from the EndpointSlices and the
+// selector, so a pod that matches but does not serve is drawn with a
+// dotted edge labelled with its endpoint state.
+func printServiceGraph(ctx context.Context, w io.Writer, src *source, namespace string, opts metav1.ListOptions) {
+	services, err := fetch(src, &corev1.ServiceList{}, namespace, opts, func() (*corev1.ServiceList, error) {
+		return src.clientset.CoreV1().Services(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	// one List each for every service's slices and pods
+	slices, err := fetch(src, &discoveryv1.EndpointSliceList{}, namespace, metav1.ListOptions{}, func() (*discoveryv1.EndpointSliceList, error) {
+		return src.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	pods, err := fetch(src, &corev1.PodList{}, namespace, metav1.ListOptions{}, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	fmt.Fprintln(w, "graph LR")
+	nodeEdges := map[string]bool{}
+	declared := map[string]bool{}
+	declare := func(id, label string) {
+		if !declared[id] {
+			declared[id] = true
+			fmt.Fprintf(w, "  %s[\"%s\"]\n", id, label)
+		}
+	}
+
+	for _, svc := range services.Items {
+		svcID := mermaidID("svc", svc.Namespace, svc.Name)
+		declare(svcID, fmt.Sprintf("svc %s<br/>%s %s", svc.Name, svc.Spec.Type, orNone(svc.Spec.ClusterIP)))
+
+		var svcSlices []discoveryv1.EndpointSlice
+		for _, slice := range slices.Items {
+			if slice.Namespace == svc.Namespace && slice.Labels[discoveryv1.LabelServiceName] == svc.Name {
+				svcSlices = append(svcSlices, slice)
+			}
+		}
+		var selected []corev1.Pod
+		if len(svc.Spec.Selector) > 0 {
+			selector := labels.SelectorFromSet(svc.Spec.Selector)
+			for _, pod := range pods.Items {
+				if pod.Namespace == svc.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+					selected = append(selected, pod)
+				}
+			}
+		}
+
+		for _, row := range buildEndpointRows(svcSlices, selected) {
+			// an endpoint with no pod behind it is drawn as its address
+			targetID, label := mermaidID("pod", svc.Namespace, row.Pod), "pod "+row.Pod
+			if row.Pod == "<none>" {
+				targetID, label = mermaidID("ip", row.IP), row.IP
+			}
+			declare(targetID, label)
+			if row.Endpoint == "ready" {
+				fmt.Fprintf(w, "  %s --> %s\n", svcID, targetID)
+			} else {
+				fmt.Fprintf(w, "  %s -. %s .-> %s\n", svcID, row.Endpoint, targetID)
+			}
+			if row.Pod != "<none>" && row.Node != "<none>" {
+				nodeID := mermaidID("node", row.Node)
+				declare(nodeID, "node "+row.Node)
+				nodeEdges[targetID+" --> "+nodeID] = true
+			}
+		}
+	}
+
+	// a pod behind several services runs on one node: one edge each
+	edges := make([]string, 0, len(nodeEdges))
+	for edge := range nodeEdges {
+		edges = append(edges, edge)
+	}
+	sort.Strings(edges)
+	for _, edge := range edges {
+		fmt.Fprintf(w, "  %s\n", edge)
+	}
+}
+
+// mermaidID joins parts into a Mermaid node ID, replacing the characters
+// Kubernetes names allow but Mermaid IDs do not.
+func mermaidID(parts ...string) string {
+	id := ""
+	for i, part := range parts {
+		if i > 0 {
+			id += "_"
+		}
+		id += mermaidUnsafe.ReplaceAllString(part, "_")
+	}
+	return id
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// printNamespaceDiff compares the objects of one resource type in
+// namespace and other by name, for spotting drift between environments
+// that share a cluster: objects only one has, and the key fields that
+// differ where both have one (images and replicas for deployments).
+func printNamespaceDiff(ctx context.Context, p *printer, src *source, resourceType, namespace, other string, opts metav1.ListOptions) {
+	left, err := diffFields(ctx, src, resourceType, namespace, opts)
+	if err != nil {
+		handleError(err)
+		return
+	}
+	right, err := diffFields(ctx, src, resourceType, other, opts)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	names := map[string]bool{}
+	for name := range left {
+		names[name] = true
+	}
+	for name := range right {
+		names[name] = true
+	}
+	var rows []NamespaceDiffRow
+	identical := 0
+	for _, name := range sortedKeys(names) {
+		l, inLeft := left[name]
+		r, inRight := right[name]
+		switch {
+		case !inRight:
+			rows = append(rows, NamespaceDiffRow{name, "object", "present", "missing"})
+		case !inLeft:
+			rows = append(rows, NamespaceDiffRow{name, "object", "missing", "present"})
+		default:
+			differs := false
+			for _, field := range diffFieldOrder[resourceType] {
+				if l[field] != r[field] {
+					rows = append(rows, NamespaceDiffRow{name, field, l[field], r[field]})
+					differs = true
+				}
+			}
+			if !differs {
+				identical++
+			}
+		}
+	}
+
+	columns := []column[NamespaceDiffRow]{
+		{"NAME", 40, func(r NamespaceDiffRow) string { return r.Name }, nil},
+		{"FIELD", 10, func(r NamespaceDiffRow) string { return r.Field }, nil},
+		{strings.ToUpper(namespace), 50, func(r NamespaceDiffRow) string { return orNone(r.Left) }, nil},
+		{strings.ToUpper(other), 0, func(r NamespaceDiffRow) string { return orNone(r.Right) }, nil},
+	}
+	if p.format == "table" {
+		fmt.Fprintf(p.w, "\n%s: %s vs %s\n", resourceType, namespace, other)
+	}
+	printRows(p, "differences", nil, columns, rows)
+	if p.format == "table" {
+		fmt.Fprintf(p.w, "Identical in both: %d\n", identical)
+	}
+}
+
+// diffFieldOrder lists the fields -diff-namespace compares per resource
+// type, in the order they are reported.
+var diffFieldOrder = map[string][]string{
+	"deployments": {"replicas", "images"},
+	"services":    {"type", "ports", "selector"},
+	"configmaps":  {"keys"},
+	"secrets":     {"type", "keys"},
+}
+
+// diffFields lists namespace's objects of resourceType with the fields
+// -diff-namespace compares, keyed by object name.
+func diffFields(ctx context.Context, src *source, resourceType, namespace string, opts metav1.ListOptions) (map[string]map[string]string, error) {
+	objects := map[string]map[string]string{}
+	switch resourceType {
+	case "deployments":
+		list, err := fetch(src, &appsv1.DeploymentList{}, namespace, opts, func() (*appsv1.DeploymentList, error) {
+			return src.clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, deployment := range list.Items {
+			var images []string
+			for _, c := range deployment.Spec.Template.Spec.Containers {
+				images = append(images, c.Name+"="+c.Image)
+			}
+			objects[deployment.Name] = map[string]string{
+				"replicas": strconv.Itoa(int(getDesiredReplicas(deployment))),
+				"images":   strings.Join(images, ","),
+			}
+		}
+	case "services":
+		list, err := fetch(src, &corev1.ServiceList{}, namespace, opts, func() (*corev1.ServiceList, error) {
+			return src.clientset.CoreV1().Services(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, svc := range list.Items {
+			var ports []string
+			for _, port := range svc.Spec.Ports {
+				ports = append(ports, fmt.Sprintf("%d:%s/%s", port.Port, port.TargetPort.String(), port.Protocol))
+			}
+			objects[svc.Name] = map[string]string{
+				"type":     string(svc.Spec.Type),
+				"ports":    strings.Join(ports, ","),
+				"selector": labels.Set(svc.Spec.Selector).String(),
+			}
+		}
+	case "configmaps":
+		list, err := fetch(src, &corev1.ConfigMapList{}, namespace, opts, func() (*corev1.ConfigMapList, error) {
+			return src.clientset.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, cm := range list.Items {
+			keys := append(sortedKeys(cm.Data), sortedKeys(cm.BinaryData)...)
+			sort.Strings(keys)
+			objects[cm.Name] = map[string]string{"keys": strings.Join(keys, ",")}
+		}
+	case "secrets":
+		list, err := fetch(src, &corev1.SecretList{}, namespace, opts, func() (*corev1.SecretList, error) {
+			return src.clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		// key names only, values are never compared
+		for _, secret := range list.Items {
+			objects[secret.Name] = map[string]string{"type": string(secret.Type), "keys": strings.Join(sortedKeys(secret.Data), ",")}
+		}
+	default:
+		return nil, fmt.Errorf("-diff-namespace supports deployments, services, configmaps and secrets, not %s", resourceType)
+	}
+	return objects, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// namespaceWatch notices, between watch ticks, that the watched namespace
+// is being deleted or is gone, so the watch can end with one clear line
+// instead of listing nothing, or failing, every interval from then on.
+type namespaceWatch struct {
+	name string
+	seen bool // the namespace existed on an earlier check
+}
+
+// check returns why the watch should end, or "" while the namespace is
+// active. A namespace that never existed is reported apart from one
+// deleted during the watch. When namespaces cannot be read, as RBAC
+// limited to one namespace often means, the watch carries on.
+func (n *namespaceWatch) check(ctx context.Context, src *source) string {
+	namespace, err := src.clientset.CoreV1().Namespaces().Get(ctx, n.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if !n.seen {
+			return fmt.Sprintf("namespace %s does not exist", n.name)
+		}
+		return fmt.Sprintf("namespace %s is gone: it was deleted during the watch", n.name)
+	}
+	if err != nil {
+		// the tick's own lists report anything else
+		return ""
+	}
+	n.seen = true
+	if namespace.Status.Phase == corev1.NamespaceTerminating {
+		since := ""
+		if namespace.DeletionTimestamp != nil {
+			since = fmt.Sprintf(" (deleted %s ago)", formatAge(namespace.DeletionTimestamp.Time))
+		}
+		return fmt.Sprintf("namespace %s is terminating%s; its objects are being removed", n.name, since)
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// NodeAlert is the JSON body posted to -alert-webhook. Text repeats the
+// alert as one line for chat webhooks that only show that field.
+type NodeAlert struct {
+	Alert       string    `json:"alert"`
+	Node        string    `json:"node"`
+	Cluster     string    `json:"cluster,omitempty"`
+	Status      string    `json:"status"`
+	Reason      string    `json:"reason,omitempty"`
+	Message     string    `json:"message,omitempty"`
+	RunningPods int       `json:"runningPods"`
+	Time        time.Time `json:"time"`
+	Text        string    `json:"text"`
+}
+
+// nodeAlerter remembers each node's readiness across watch ticks and
+// alerts when a node goes from Ready to NotReady. It outlives the
+// per-tick printer.
+type nodeAlerter struct {
+	webhook string // optional
+	ready   map[string]bool
+}
+
+func newNodeAlerter(webhook string) *nodeAlerter {
+	return &nodeAlerter{webhook: webhook, ready: map[string]bool{}}
+}
+
+// observe records this tick's node readiness and alerts on every node
+// that was Ready on the previous tick and is not now. Nodes seen for the
+// first time only set the baseline. cluster keeps the nodes of a
+// -resource nodes@CLUSTER section apart.
+func (a *nodeAlerter) observe(ctx context.Context, w io.Writer, src *source, cluster string, nodes []corev1.Node) {
+	for _, node := range nodes {
+		status := getNodeStatus(node)
+		key := atCluster(node.Name, cluster)
+		wasReady, seen := a.ready[key]
+		a.ready[key] = status == "Ready"
+		if !seen || !wasReady || status == "Ready" {
+			continue
+		}
+
+		alert := NodeAlert{Alert: "NodeNotReady", Node: node.Name, Cluster: cluster, Status: status, RunningPods: -1, Time: time.Now().UTC()}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady {
+				alert.Reason, alert.Message = condition.Reason, condition.Message
+			}
+		}
+		pods := "unknown pods"
+		if running, err := runningPodsOnNode(ctx, src, node.Name); err == nil {
+			alert.RunningPods = running
+			pods = fmt.Sprintf("%d pods", running)
+		}
+		detail := alert.Message
+		if detail == "" {
+			detail = alert.Reason
+		}
+		alert.Text = fmt.Sprintf("NODE NOT READY %s: %s (%s running on it)", key, detail, pods)
+		fmt.Fprintln(w, alert.Text)
+
+		if a.webhook != "" {
+			if err := postAlert(ctx, a.webhook, alert); err != nil {
+				fmt.Fprintf(w, "Warning: alert to %s failed: %v\n", a.webhook, err)
+			}
+		}
+	}
+}
+
+// runningPodsOnNode counts the Running pods scheduled to node in every
+// namespace. It bypasses fetch so this side lookup stays out of the
+// latency and churn figures.
+func runningPodsOnNode(ctx context.Context, src *source, node string) (int, error) {
+	opts := metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node).String()}
+	pods := &corev1.PodList{}
+	var err error
+	if src.dump != nil {
+		err = src.dump.into(pods, "", opts)
+	} else {
+		pods, err = src.clientset.CoreV1().Pods("").List(ctx, opts)
+	}
+	if err != nil {
+		return 0, err
+	}
+	running := 0
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			running++
+		}
+	}
+	return running, nil
+}
+
+func postAlert(ctx context.Context, webhook string, alert NodeAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// problemNotifier rings the terminal bell and shows a desktop notification
+// for -notify when an object becomes unhealthy during a watch. Problems
+// present on the first tick are the baseline, and an object is not
+// notified again within cooldown of its last notification. It outlives
+// the per-tick printer.
+type problemNotifier struct {
+	cooldown time.Duration
+	previous map[string]string // last tick's problems, nil before the first
+	current  map[string]string
+	notified map[string]time.Time
+}
+
+func newProblemNotifier(cooldown time.Duration) *problemNotifier {
+	return &problemNotifier{cooldown: cooldown, current: map[string]string{}, notified: map[string]time.Time{}}
+}
+
+// observe records the problems in one listed page of pods, nodes or
+// deployments; fetch calls it for every list. cluster keeps the objects
+// of a TYPE@CLUSTER source apart.
+func (n *problemNotifier) observe(cluster string, list runtime.Object) {
+	for _, object := range listProblems(list) {
+		key := object.Kind + " " + atCluster(strings.TrimPrefix(object.Namespace+"/"+object.Name, "/"), cluster)
+		n.current[key] = object.Reason
+	}
+}
+
+// flush notifies the problems that are new since the last tick and starts
+// the next tick from empty.
+func (n *problemNotifier) flush() {
+	var fresh []string
+	now := time.Now()
+	if n.previous != nil {
+		for _, key := range sortedKeys(n.current) {
+			if _, ongoing := n.previous[key]; ongoing || now.Sub(n.notified[key]) < n.cooldown {
+				continue
+			}
+			n.notified[key] = now
+			fresh = append(fresh, fmt.Sprintf("%s: %s", key, n.current[key]))
+		}
+	}
+	n.previous, n.current = n.current, map[string]string{}
+	if len(fresh) == 0 {
+		return
+	}
+
+	// the bell goes to stderr so it still reaches the terminal when the
+	// tables go to -output-file
+	fmt.Fprint(os.Stderr, "\a")
+	title := fmt.Sprintf("k8s-monitor: %d new problem(s)", len(fresh))
+	desktopNotify(title, strings.Join(fresh, "\n"))
+}
+
+// listProblems returns the unhealthy objects of a pod, node or deployment
+// list, judged as -summary does.
+func listProblems(list runtime.Object) []UnhealthyObject {
+	var unhealthy []UnhealthyObject
+	switch list := list.(type) {
+	case *corev1.PodList:
+		for _, pod := range list.Items {
+			if problem := getPodProblem(pod); problem != "" {
+				unhealthy = append(unhealthy, UnhealthyObject{"pod", pod.Namespace, pod.Name, problem})
+			}
+		}
+	case *corev1.NodeList:
+		for _, node := range list.Items {
+			if status := getNodeStatus(node); status != "Ready" {
+				unhealthy = append(unhealthy, UnhealthyObject{"node", "", node.Name, status})
+			}
+		}
+	case *appsv1.DeploymentList:
+		for _, deployment := range list.Items {
+			if desired := getDesiredReplicas(deployment); deployment.Status.AvailableReplicas < desired {
+				unhealthy = append(unhealthy, UnhealthyObject{"deployment", deployment.Namespace, deployment.Name,
+					fmt.Sprintf("%d/%d available", deployment.Status.AvailableReplicas, desired)})
+			}
+		}
+	}
+	return unhealthy
+}
+
+// desktopNotify shows a notification with notify-send on Linux or
+// osascript on macOS, whichever is installed. It does not wait for the
+// command.
+func desktopNotify(title, body string) {
+	var cmd *exec.Cmd
+	if _, err := exec.LookPath("notify-send"); err == nil {
+		cmd = exec.Command("notify-send", title, body)
+	} else if _, err := exec.LookPath("osascript"); err == nil {
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", body, title))
+	} else {
+		return
+	}
+	if err := cmd.Start(); err == nil {
+		go cmd.Wait()
+	}
+}
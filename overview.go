@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var overviewColumns = []column[OverviewRow]{
+	{"NAMESPACE", 30, func(r OverviewRow) string { return r.Namespace }, nil},
+	{"PODS", 12, func(r OverviewRow) string { return fmt.Sprintf("%d/%d", r.RunningPods, r.TotalPods) }, nil},
+	{"DEPLOYMENTS", 12, func(r OverviewRow) string {
+		return fmt.Sprintf("%d/%d", r.AvailableDeployments, r.TotalDeployments)
+	}, nil},
+	{"QUOTA", 24, func(r OverviewRow) string { return joinOrNone(r.QuotaWarnings) }, nil},
+	{"PROBLEMS", 0, func(r OverviewRow) string {
+		if len(r.Problems) == 0 {
+			return "<none>"
+		}
+		return strings.Join(r.Problems, ", ")
+	}, func(r OverviewRow) string { return withoutDuration(strings.Join(r.Problems, ", ")) }},
+}
+
+// printOverview prints one line per namespace with pod and deployment
+// counts, pods quotas that are nearly used up and every pod in a bad
+// state. Everything is fetched with a single cluster-wide List per type
+// and aggregated client-side.
+func printOverview(ctx context.Context, p *printer, src *source, opts metav1.ListOptions) {
+	namespaces, err := fetch(src, &corev1.NamespaceList{}, "", metav1.ListOptions{}, func() (*corev1.NamespaceList, error) {
+		return src.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	pods, err := fetch(src, &corev1.PodList{}, "", opts, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods("").List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	deployments, err := fetch(src, &appsv1.DeploymentList{}, "", opts, func() (*appsv1.DeploymentList, error) {
+		return src.clientset.AppsV1().Deployments("").List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	overview := map[string]*OverviewRow{}
+	get := func(namespace string) *OverviewRow {
+		if overview[namespace] == nil {
+			overview[namespace] = &OverviewRow{Namespace: namespace}
+		}
+		return overview[namespace]
+	}
+
+	for _, ns := range namespaces.Items {
+		get(ns.Name)
+	}
+	for _, pod := range pods.Items {
+		entry := get(pod.Namespace)
+		entry.TotalPods++
+		if pod.Status.Phase == corev1.PodRunning {
+			entry.RunningPods++
+		}
+		if problem := getPodProblem(pod); problem != "" {
+			entry.Problems = append(entry.Problems, fmt.Sprintf("%s(%s)", pod.Name, problem))
+		}
+	}
+	for _, deployment := range deployments.Items {
+		entry := get(deployment.Namespace)
+		entry.TotalDeployments++
+		if deployment.Status.AvailableReplicas >= getDesiredReplicas(deployment) {
+			entry.AvailableDeployments++
+		}
+	}
+
+	quotaWarnings, err := podQuotaWarnings(ctx, src, "")
+	if err != nil {
+		handleError(err)
+		return
+	}
+	for _, warning := range quotaWarnings {
+		entry := get(warning.namespace)
+		entry.QuotaWarnings = append(entry.QuotaWarnings, warning.String())
+	}
+
+	rows := make([]OverviewRow, 0, len(overview))
+	for _, entry := range overview {
+		rows = append(rows, *entry)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Namespace < rows[j].Namespace })
+	printRows(p, "namespaces", nil, overviewColumns, rows)
+}
+
+// badWaitingReasons are container waiting reasons that will not resolve
+// on their own.
+var badWaitingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// terminatingGrace is how long past its grace period a pod may stay
+// Terminating before it is a problem, usually a finalizer nobody removes.
+var terminatingGrace = 5 * time.Minute
+
+// notReadyGrace is how long a Running pod may stay not ready before it is
+// a problem, so pods just starting up are not reported.
+var notReadyGrace = time.Minute
+
+// getPodProblem returns why a pod is in a bad state, or "" if it is fine.
+func getPodProblem(pod corev1.Pod) string {
+	if pod.DeletionTimestamp != nil && time.Since(pod.DeletionTimestamp.Time) > terminatingGrace {
+		problem := "Terminating for " + formatAge(terminatingSince(pod))
+		if len(pod.Finalizers) > 0 {
+			problem += ", finalizers: " + strings.Join(pod.Finalizers, ",")
+		}
+		return problem
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && badWaitingReasons[status.State.Waiting.Reason] {
+			return status.State.Waiting.Reason
+		}
+	}
+
+	// a pod awaiting its startup probe is Starting, not a problem yet
+	if since, notReady := notReadySince(pod); notReady && getPodStatus(pod) == "NotReady" && time.Since(since) > notReadyGrace {
+		problem := "Running but not ready for " + formatAge(since)
+		if containers := notReadyContainers(pod); len(containers) > 0 {
+			problem += ", containers: " + strings.Join(containers, ",")
+		}
+		return problem
+	}
+	if isEvicted(pod) && pod.Status.Message != "" {
+		return "Evicted: " + pod.Status.Message
+	}
+	switch pod.Status.Phase {
+	case corev1.PodFailed, corev1.PodUnknown, corev1.PodPending:
+		if pod.Status.Reason != "" {
+			return pod.Status.Reason
+		}
+		return string(pod.Status.Phase)
+	}
+	return ""
+}
+
+func getDesiredReplicas(deployment appsv1.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+	return *deployment.Spec.Replicas
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var pendingVolumeColumns = []column[PendingVolumeRow]{
+	{"POD", 40, func(r PendingVolumeRow) string { return r.Pod }, nil},
+	{"PVC", 40, func(r PendingVolumeRow) string { return r.PVC }, nil},
+	{"PVC-STATUS", 12, func(r PendingVolumeRow) string { return r.Status }, nil},
+	{"STORAGECLASS", 0, func(r PendingVolumeRow) string { return orNone(r.StorageClass) }, nil},
+}
+
+// printPendingVolumes lists the Pending pods that wait on a
+// PersistentVolumeClaim that is not Bound, with the claim's phase and
+// storage class, so a pod stuck on its volume needs no cross-referencing.
+// A claim that does not exist yet shows as Missing.
+func printPendingVolumes(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	pods, err := fetch(src, &corev1.PodList{}, namespace, opts, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	claims, err := fetch(src, &corev1.PersistentVolumeClaimList{}, namespace, metav1.ListOptions{}, func() (*corev1.PersistentVolumeClaimList, error) {
+		return src.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	byName := map[string]corev1.PersistentVolumeClaim{}
+	for _, claim := range claims.Items {
+		byName[claim.Namespace+"/"+claim.Name] = claim
+	}
+
+	var rows []PendingVolumeRow
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			name := ""
+			if volume.PersistentVolumeClaim != nil {
+				name = volume.PersistentVolumeClaim.ClaimName
+			} else if volume.Ephemeral != nil {
+				// generic ephemeral volumes get a claim named after the pod
+				name = pod.Name + "-" + volume.Name
+			} else {
+				continue
+			}
+
+			row := PendingVolumeRow{Namespace: pod.Namespace, Pod: pod.Name, PVC: name, Status: "Missing"}
+			if claim, ok := byName[pod.Namespace+"/"+name]; ok {
+				if claim.Status.Phase == corev1.ClaimBound {
+					continue
+				}
+				row.Status = string(claim.Status.Phase)
+				if claim.Spec.StorageClassName != nil {
+					row.StorageClass = *claim.Spec.StorageClassName
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	printRows(p, "pvc-pending", nil, pendingVolumeColumns, rows)
+}
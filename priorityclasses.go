@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var priorityClassColumns = []column[PriorityClassRow]{
+	{"NAME", 40, func(r PriorityClassRow) string { return r.Name }, nil},
+	{"VALUE", 12, func(r PriorityClassRow) string { return strconv.Itoa(int(r.Value)) }, nil},
+	{"GLOBAL-DEFAULT", 16, func(r PriorityClassRow) string { return strconv.FormatBool(r.GlobalDefault) }, nil},
+	{"PREEMPTIONPOLICY", 22, func(r PriorityClassRow) string { return r.PreemptionPolicy }, nil},
+	{"AGE", 10, func(r PriorityClassRow) string { return r.Age }, ignored[PriorityClassRow]},
+}
+
+// listPriorityClasses lists the cluster's PriorityClasses, highest value
+// first as the scheduler ranks them.
+func listPriorityClasses(ctx context.Context, p *printer, src *source, opts metav1.ListOptions) {
+	classes, err := fetch(src, &schedulingv1.PriorityClassList{}, "", opts, func() (*schedulingv1.PriorityClassList, error) {
+		return src.clientset.SchedulingV1().PriorityClasses().List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []PriorityClassRow
+	for _, class := range classes.Items {
+		rows = append(rows, newPriorityClassRow(class))
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Value > rows[j].Value })
+	printRows(p, "priorityclasses", classes, priorityClassColumns, rows)
+}
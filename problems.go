@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var problemColumns = []column[ProblemRow]{
+	{"KIND", 22, func(r ProblemRow) string { return r.Kind }, nil},
+	{"NAME", 40, func(r ProblemRow) string { return strings.TrimPrefix(r.Namespace+"/"+r.Name, "/") }, nil},
+	{"PROBLEM", 50, func(r ProblemRow) string { return r.Problem }, func(r ProblemRow) string { return withoutDuration(r.Problem) }},
+	{"LAST-WARNING", 0, func(r ProblemRow) string {
+		e := r.Event
+		if e == nil {
+			return "<none>"
+		}
+		seen := formatAge(e.LastSeen) + " ago"
+		if e.Count > 1 {
+			seen = fmt.Sprintf("x%d, last %s", e.Count, seen)
+		}
+		return fmt.Sprintf("%s: %s (%s)", e.Reason, e.Message, seen)
+	}, func(r ProblemRow) string {
+		if e := r.Event; e != nil {
+			return fmt.Sprintf("%s: %s (x%d, last at %s)", e.Reason, e.Message, e.Count, e.LastSeen.Format(time.RFC3339))
+		}
+		return ""
+	}},
+}
+
+// printProblems lists only the unhealthy objects of the given types, each
+// with the most recent Warning event about it, so the state and its likely
+// cause are read together.
+func printProblems(ctx context.Context, p *printer, src *source, namespace string, resources []string, opts metav1.ListOptions) {
+	var unhealthy []UnhealthyObject
+	for _, resource := range resources {
+		_, objects, ok, err := checkHealth(ctx, src, resource, namespace, opts)
+		if err != nil {
+			handleError(err)
+			return
+		}
+		if !ok {
+			fmt.Printf("Error: -only-problems does not support %s\n", resource)
+			os.Exit(1)
+		}
+		unhealthy = append(unhealthy, objects...)
+	}
+
+	warnings, err := latestWarnings(ctx, src, namespace)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []ProblemRow
+	for _, object := range unhealthy {
+		row := ProblemRow{Kind: object.Kind, Namespace: object.Namespace, Name: object.Name, Problem: object.Reason}
+		if event, ok := warnings[warningKey(object.Kind, object.Namespace, object.Name)]; ok {
+			row.Event = &WarningEvent{event.Reason, event.Message, event.Count, eventTime(event)}
+		}
+		rows = append(rows, row)
+	}
+	printRows(p, "problems", nil, problemColumns, rows)
+}
+
+// latestWarnings returns the most recent Warning event about each object
+// in namespace, keyed by warningKey.
+func latestWarnings(ctx context.Context, src *source, namespace string) (map[string]corev1.Event, error) {
+	events, err := fetch(src, &corev1.EventList{}, namespace, metav1.ListOptions{}, func() (*corev1.EventList, error) {
+		return src.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := map[string]corev1.Event{}
+	for _, event := range events.Items {
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+		involved := event.InvolvedObject
+		key := warningKey(involved.Kind, involved.Namespace, involved.Name)
+		if previous, ok := warnings[key]; ok && eventTime(event).Before(eventTime(previous)) {
+			continue
+		}
+		warnings[key] = event
+	}
+	return warnings, nil
+}
+
+// warningKey matches an event's involvedObject ("Pod") with an
+// UnhealthyObject ("pod").
+func warningKey(kind, namespace, name string) string {
+	return strings.ToLower(kind) + " " + namespace + "/" + name
+}
+
+// eventTime is when an event was last seen; events from the events.k8s.io
+// API only set eventTime.
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.EventTime.Time
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// pushGateway collects one tick's object counts and pushes them to a
+// Prometheus Pushgateway, for runs too short-lived to be scraped, such as
+// a CronJob.
+type pushGateway struct {
+	url      string                    // the group URL, job and instance included
+	objects  map[string]int            // per kind, and cluster for TYPE@CLUSTER
+	statuses map[string]map[string]int // kind[@cluster] -> STATUS -> count
+}
+
+func newPushGateway(gateway, job, instance string) (*pushGateway, error) {
+	u, err := url.Parse(gateway)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid -push-gateway URL %q", gateway)
+	}
+	u = u.JoinPath("metrics")
+	u = u.JoinPath(groupLabel("job", job)...)
+	u = u.JoinPath(groupLabel("instance", instance)...)
+	return &pushGateway{url: u.String()}, nil
+}
+
+// groupLabel is a label's path segments in a Pushgateway group URL. Values
+// that cannot be a path segment use the gateway's base64 form.
+func groupLabel(name, value string) []string {
+	if value == "" || strings.Contains(value, "/") {
+		return []string{name + "@base64", base64.RawURLEncoding.EncodeToString([]byte(value))}
+	}
+	return []string{name, value}
+}
+
+// recordMetrics counts rows, and rows per STATUS for kinds that have one.
+func recordMetrics[T any](g *pushGateway, kind, cluster string, columns []column[T], rows []T) {
+	g.count(kind, cluster, len(rows))
+	if statuses, ok := rowStatuses(columns, rows); ok {
+		counts := map[string]int{}
+		for _, s := range statuses {
+			counts[s.status]++
+		}
+		g.statuses[atCluster(kind, cluster)] = counts
+	}
+}
+
+// count records how many objects of a kind were listed, for views such as
+// collapsed sections that have no rows.
+func (g *pushGateway) count(kind, cluster string, n int) {
+	if g.objects == nil {
+		g.objects = map[string]int{}
+		g.statuses = map[string]map[string]int{}
+	}
+	g.objects[atCluster(kind, cluster)] = n
+}
+
+// sectionLabels are the kind label, and the cluster label for a
+// TYPE@CLUSTER section, of a key of objects or statuses.
+func sectionLabels(section string) string {
+	kind, cluster, ok := strings.Cut(section, "@")
+	if !ok {
+		return fmt.Sprintf("kind=\"%s\"", escapeLabel(kind))
+	}
+	return fmt.Sprintf("kind=\"%s\",cluster=\"%s\"", escapeLabel(kind), escapeLabel(cluster))
+}
+
+// push replaces the group's metrics with this tick's and starts the next
+// tick from empty.
+func (g *pushGateway) push(ctx context.Context) error {
+	var body bytes.Buffer
+	fmt.Fprintln(&body, "# HELP k8s_monitor_objects Objects listed, per resource type.")
+	fmt.Fprintln(&body, "# TYPE k8s_monitor_objects gauge")
+	for _, section := range sortedKeys(g.objects) {
+		fmt.Fprintf(&body, "k8s_monitor_objects{%s} %d\n", sectionLabels(section), g.objects[section])
+	}
+	fmt.Fprintln(&body, "# HELP k8s_monitor_objects_by_status Objects listed, per resource type and STATUS.")
+	fmt.Fprintln(&body, "# TYPE k8s_monitor_objects_by_status gauge")
+	for _, section := range sortedKeys(g.statuses) {
+		for _, status := range sortedKeys(g.statuses[section]) {
+			fmt.Fprintf(&body, "k8s_monitor_objects_by_status{%s,status=\"%s\"} %d\n",
+				sectionLabels(section), escapeLabel(status), g.statuses[section][status])
+		}
+	}
+	fmt.Fprintln(&body, "# HELP k8s_monitor_last_push_timestamp_seconds When these metrics were pushed.")
+	fmt.Fprintln(&body, "# TYPE k8s_monitor_last_push_timestamp_seconds gauge")
+	fmt.Fprintf(&body, "k8s_monitor_last_push_timestamp_seconds %d\n", time.Now().Unix())
+	g.objects, g.statuses = nil, nil
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, g.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("pushgateway returned %s", resp.Status)
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
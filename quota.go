@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// quotaWarnRatio is the share of a pods quota above which a namespace is
+// reported as about to stop scheduling new pods.
+const quotaWarnRatio = 0.9
+
+// quotaWarning is a ResourceQuota on pods that is nearly or fully used.
+type quotaWarning struct {
+	namespace string
+	quota     string
+	used      int64
+	hard      int64
+}
+
+func (q quotaWarning) String() string {
+	return fmt.Sprintf("pods %d/%d (%s)", q.used, q.hard, q.quota)
+}
+
+// podQuotaWarnings compares each ResourceQuota's used pods with its limit
+// on pods, or on count/pods when that is the one set. The used count is the
+// quota's own status, which the API server keeps for the quota's scopes and
+// which leaves out Succeeded and Failed pods. An empty namespace checks all.
+func podQuotaWarnings(ctx context.Context, src *source, namespace string) ([]quotaWarning, error) {
+	quotas, err := fetch(src, &corev1.ResourceQuotaList{}, namespace, metav1.ListOptions{}, func() (*corev1.ResourceQuotaList, error) {
+		return src.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []quotaWarning
+	for _, quota := range quotas.Items {
+		for _, resource := range []corev1.ResourceName{corev1.ResourcePods, "count/pods"} {
+			hard, ok := quota.Status.Hard[resource]
+			if !ok {
+				hard, ok = quota.Spec.Hard[resource]
+			}
+			if !ok {
+				continue
+			}
+			// the first limit found stands for the quota, so a quota setting
+			// both is reported once
+			used := quota.Status.Used[resource]
+			if hard.Value() > 0 && float64(used.Value()) >= quotaWarnRatio*float64(hard.Value()) {
+				warnings = append(warnings, quotaWarning{quota.Namespace, quota.Name, used.Value(), hard.Value()})
+			}
+			break
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// printRaw writes the list as a kind: List document holding the objects
+// as the API returned them, rather than the flattened rows of -o json, so
+// it can be piped into kubectl apply and other Kubernetes tools.
+// managedFields are stripped unless showManagedFields is set: they are
+// rarely useful to a human and often the bulk of the output.
+func printRaw(w io.Writer, list runtime.Object, showManagedFields bool) error {
+	// trim a copy, the list may be shared with later ticks
+	list = list.DeepCopyObject()
+	items := []runtime.Object{}
+	if err := meta.EachListItem(list, func(obj runtime.Object) error {
+		// typed clients drop apiVersion and kind when decoding; tools
+		// reading the output need them back
+		if obj.GetObjectKind().GroupVersionKind().Empty() {
+			kinds, _, err := scheme.Scheme.ObjectKinds(obj)
+			if err != nil {
+				return err
+			}
+			obj.GetObjectKind().SetGroupVersionKind(kinds[0])
+		}
+		if !showManagedFields {
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return err
+			}
+			accessor.SetManagedFields(nil)
+		}
+		items = append(items, obj)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	resourceVersion := ""
+	if listMeta, err := meta.ListAccessor(list); err == nil {
+		resourceVersion = listMeta.GetResourceVersion()
+	}
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"metadata":   map[string]string{"resourceVersion": resourceVersion},
+		"items":      items,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// readinessFeed reports, for -readiness, each pod that becomes Ready
+// during the watch, with how long it took since it was created, and each
+// pod that stops being Ready, so a rollout can be followed pod by pod.
+// It outlives the per-tick printer.
+type readinessFeed struct {
+	started time.Time
+	color   bool                       // green and red lines, on a terminal
+	ready   map[string]map[string]bool // per cluster, the pods seen and whether they were ready
+}
+
+func newReadinessFeed(color bool) *readinessFeed {
+	return &readinessFeed{started: time.Now(), color: color, ready: map[string]map[string]bool{}}
+}
+
+// observe prints a line for every pod whose readiness changed since the
+// previous tick. Pods already there on the first tick are the baseline,
+// but a pod created during the watch and first seen ready is reported:
+// it became ready between two ticks. cluster keeps the pods of a
+// -resource pods@CLUSTER section apart.
+func (f *readinessFeed) observe(w io.Writer, cluster string, pods []corev1.Pod) {
+	previous := f.ready[cluster]
+	current := map[string]bool{}
+	for _, pod := range pods {
+		// a deleted pod going unready is the rollout replacing it
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		key := pod.Namespace + "/" + pod.Name
+		ready := podContainersReady(pod)
+		current[key] = ready
+		was, known := previous[key]
+		if known && was == ready {
+			continue
+		}
+		if !known && (!ready || pod.CreationTimestamp.Time.Before(f.started.Truncate(time.Second))) {
+			continue
+		}
+
+		if cluster != "" {
+			key += "@" + cluster
+		}
+		if ready {
+			took := containersReadyTime(pod).Sub(pod.CreationTimestamp.Time).Round(time.Second)
+			f.print(w, "\033[32m", fmt.Sprintf("✓ pod %s now Ready (took %s since creation)", key, took))
+		} else {
+			f.print(w, "\033[31m", fmt.Sprintf("✗ pod %s lost readiness (not ready: %s)", key, joinOrNone(notReadyContainers(pod))))
+		}
+	}
+	// pods gone since are forgotten
+	f.ready[cluster] = current
+}
+
+func (f *readinessFeed) print(w io.Writer, color, line string) {
+	line = time.Now().Format("15:04:05") + " " + line
+	if f.color {
+		line = color + line + "\033[0m"
+	}
+	fmt.Fprintln(w, line)
+}
+
+// podContainersReady reports whether every container of a running pod is
+// ready.
+func podContainersReady(pod corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodRunning && getReadyContainers(pod.Status.ContainerStatuses) == len(pod.Spec.Containers)
+}
+
+// containersReadyTime is when the pod's containers last all became ready,
+// from its ContainersReady condition, or now without one.
+func containersReadyTime(pod corev1.Pod) time.Time {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.ContainersReady && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return time.Now()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// reconnector retries API calls that fail because the API server went away,
+// as it does during a control-plane upgrade, so a watch survives the
+// restart instead of printing an error every interval.
+type reconnector struct {
+	w    io.Writer
+	done <-chan struct{} // stops retrying, e.g. on Ctrl+C
+
+	mu    sync.Mutex
+	state string // for the watch header; "" until the first failure
+}
+
+// Connection states shown in the watch header.
+const (
+	stateConnected    = "connected"
+	stateReconnecting = "reconnecting"
+	stateExpired      = "credentials expired"
+)
+
+// status is the connection state for the watch header.
+func (r *reconnector) status() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state == "" {
+		return stateConnected
+	}
+	return r.state
+}
+
+// setStatus records the connection state and returns the previous one.
+func (r *reconnector) setStatus(state string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous := r.state
+	r.state = state
+	if previous == "" {
+		previous = stateConnected
+	}
+	return previous
+}
+
+// isDisconnect reports whether err means the API server could not be
+// reached at all, as opposed to rejecting the request.
+func isDisconnect(err error) bool {
+	return utilnet.IsConnectionRefused(err) || utilnet.IsConnectionReset(err) ||
+		utilnet.IsProbableEOF(err) || apierrors.IsServiceUnavailable(err)
+}
+
+// retry calls call until it succeeds, fails for another reason, or done is
+// closed, backing off exponentially from 1s to 30s with jitter so a fleet
+// of monitors does not reconnect in lockstep. A call the API server
+// rejects as unauthorized is retried once with credentials reloaded by
+// creds, when set.
+func retry[L any](r *reconnector, creds *credentialReloader, call func() (L, error)) (L, error) {
+	result, err := call()
+	if apierrors.IsUnauthorized(err) && creds != nil {
+		return reauthenticate(r, creds, call)
+	}
+	if err == nil {
+		r.setStatus(stateConnected)
+		return result, err
+	}
+	if !isDisconnect(err) {
+		return result, err
+	}
+	r.setStatus(stateReconnecting)
+
+	backoff := wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.5, Steps: math.MaxInt32, Cap: 30 * time.Second}
+	started := time.Now()
+	for attempt := 1; isDisconnect(err); attempt++ {
+		delay := backoff.Step()
+		fmt.Fprintf(r.w, "API server unreachable (%v), reconnecting in %s (attempt %d)...\n", err, delay.Round(100*time.Millisecond), attempt)
+		select {
+		case <-r.done:
+			return result, err
+		case <-time.After(delay):
+		}
+		result, err = call()
+	}
+	r.setStatus(stateConnected)
+	if err == nil {
+		fmt.Fprintf(r.w, "Reconnected to the API server after %s\n", time.Since(started).Round(time.Second))
+	}
+	return result, err
+}
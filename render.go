@@ -0,0 +1,549 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// printer renders rows in the format selected with -output.
+type printer struct {
+	w             io.Writer
+	format        string
+	fields        []string              // -fields; nil keeps every column
+	savedFields   map[string][]string   // the config file's fields, per kind, when -fields is not given
+	customColumns []customColumn        // -o custom-columns
+	annotations   []string              // -show-annotations keys, shown as extra columns
+	onChange      *changeFilter         // -watch-on-change-only
+	throttle      *outputThrottle       // -max-output-rate, for the -watch-on-change-only lines
+	wide          bool                  // -wide adds each resource's extra columns
+	log           io.Writer             // -log-file; receives every tick as a JSON line
+	managedFields bool                  // -show-managed-fields keeps metadata.managedFields in -o raw
+	transitions   *transitionLog        // -transitions
+	explain       bool                  // -explain
+	flapping      *flapDetector         // -detect-flapping
+	rollout       *rolloutTracker       // -rollout
+	validate      bool                  // -validate
+	metrics       *pushGateway          // -push-gateway
+	restartSpikes *restartSpikeDetector // -detect-restart-spikes
+	restartRate   *restartRateTracker   // watch mode
+	sorter        *rowSorter            // -sort-by, -pin and -sticky-sort
+	scheduling    *schedulingTracker    // -scheduling-latency
+	imagePulls    *imagePullTracker     // -slow-image-pulls
+	readiness     *readinessFeed        // -readiness
+	sparklines    *countHistory         // -sparkline
+	statuses      map[string]bool       // -status, for pods
+	nodeAlerts    *nodeAlerter          // watch mode
+	dataChanges   *dataTracker          // watch mode
+	audit         *auditLog             // -audit-file
+	samples       *sampleStore          // -sqlite
+	groupBy       string                // -group-by field
+	cluster       string                // the CLUSTER of a -resource TYPE@CLUSTER section
+}
+
+// columnFields returns the columns to show for kind: -fields, else the
+// config file's fields for kind, nil keeping every column. Views such as
+// -containers render a kind of their own, so the fields saved for pods do
+// not reach them.
+func (p *printer) columnFields(kind string) []string {
+	if p.fields != nil {
+		return p.fields
+	}
+	return p.savedFields[kind]
+}
+
+// column is one table column: its header, padded width (0 for the last,
+// free-form column) and how to read the cell from a row. Cells that count
+// time as it passes, such as "Running (5m)", also have a stable value,
+// what -watch-on-change-only compares between ticks; nil compares the
+// cell itself.
+type column[T any] struct {
+	header string
+	width  int
+	value  func(T) string
+	stable func(T) string
+}
+
+// ignored is the stable value of a column that only counts time, such as
+// AGE, so that it is never reported as a change.
+func ignored[T any](T) string {
+	return ""
+}
+
+// problemDuration is how long a problem has lasted in its description,
+// "Terminating for 3h", as formatAge writes it.
+var problemDuration = regexp.MustCompile(` for [0-9]+[smhd]\b`)
+
+// withoutDuration is a problem description less how long it has lasted.
+func withoutDuration(problem string) string {
+	return problemDuration.ReplaceAllString(problem, "")
+}
+
+// withoutDetail is a cell less the detail in parentheses after it, such as
+// how long a pod has been terminating.
+func withoutDetail(cell string) string {
+	cell, _, _ = strings.Cut(cell, " (")
+	return cell
+}
+
+// printRows writes rows as a table followed by a total line, or as a JSON
+// array of the row structs. Formats that work on whole objects, such as
+// custom-columns and raw, render list instead; it may be nil for
+// synthesized views.
+func printRows[T any](p *printer, kind string, list runtime.Object, columns []column[T], rows []T) {
+	if p.format == "custom-columns" || p.format == "raw" {
+		if list == nil {
+			fmt.Printf("Error: -o %s is not supported for %s\n", p.format, kind)
+			os.Exit(1)
+		}
+		var err error
+		if p.format == "raw" {
+			err = printRaw(p.w, list, p.managedFields)
+		} else {
+			err = printCustomColumns(p.w, p.customColumns, list)
+		}
+		if err != nil {
+			handleError(err)
+		}
+		return
+	}
+
+	if p.metrics != nil {
+		recordMetrics(p.metrics, kind, p.cluster, columns, rows)
+	}
+	if p.samples != nil {
+		recordSamples(p.samples, kind, p.cluster, columns, rows)
+	}
+	if p.audit != nil {
+		if err := recordAudit(p.audit, kind, p.cluster, columns, rows); err != nil {
+			handleError(err)
+		}
+	}
+
+	// transitions and flapping read STATUS even when -fields leaves it
+	// out, and are printed after the table
+	if p.transitions != nil && p.format == "table" {
+		defer recordTransitions(p.w, p.transitions, kind, p.cluster, columns, rows)
+	}
+	if p.flapping != nil && p.format == "table" {
+		defer recordFlaps(p.w, p.flapping, kind, p.cluster, columns, rows)
+	}
+
+	// sort on the full columns, -sort-by need not be among -fields
+	var marked []bool
+	if p.sorter != nil {
+		sorted, changed, err := sortRows(p.sorter, kind, columns, rows)
+		if err != nil {
+			fmt.Printf("Error: %v (%s)\n", err, kind)
+			os.Exit(1)
+		}
+		rows, marked = sorted, changed
+	}
+	var groups []string
+	if p.groupBy != "" && p.format == "table" {
+		grouped, groupedMarks, values, err := groupRows(p.groupBy, columns, rows, marked)
+		if err != nil {
+			fmt.Printf("Error: %v (%s)\n", err, kind)
+			os.Exit(1)
+		}
+		rows, marked, groups = grouped, groupedMarks, values
+	}
+
+	if fields := p.columnFields(kind); fields != nil {
+		selected, err := selectColumns(columns, fields)
+		if err != nil {
+			fmt.Printf("Error: %v (%s)\n", err, kind)
+			os.Exit(1)
+		}
+		columns = selected
+	}
+	if len(p.annotations) > 0 {
+		columns = append(columns[:len(columns):len(columns)], annotationColumns[T](p.annotations)...)
+	}
+	if p.cluster != "" {
+		cluster := column[T]{"CLUSTER", 12, func(T) string { return p.cluster }, nil}
+		columns = append([]column[T]{cluster}, columns...)
+	}
+
+	if p.log != nil {
+		logRows(p.log, kind, p.cluster, rows)
+	}
+
+	// After the first snapshot, tables print only what changed and other
+	// formats print nothing unless something did
+	if p.onChange != nil {
+		current := newSnapshot(columns, rows)
+		if previous := p.onChange.swap(atCluster(kind, p.cluster), current); previous != nil {
+			if p.format == "table" && p.throttle != nil {
+				w := p.throttle.writer(p.w)
+				printDiff(w, kind, previous, current)
+				w.flush()
+				return
+			}
+			if p.format == "table" {
+				printDiff(p.w, kind, previous, current)
+				return
+			}
+			if previous.equal(current) {
+				return
+			}
+		}
+	}
+
+	if p.format == "json" {
+		if rows == nil {
+			rows = []T{}
+		}
+		var data []byte
+		var err error
+		if p.cluster != "" {
+			data, err = json.MarshalIndent(withCluster(rows, p.cluster), "", "  ")
+		} else {
+			data, err = json.MarshalIndent(rows, "", "  ")
+		}
+		if err != nil {
+			handleError(err)
+			return
+		}
+		fmt.Fprintln(p.w, string(data))
+		return
+	}
+	if p.format == "markdown" {
+		printMarkdown(p.w, kind, columns, rows)
+		return
+	}
+
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.header
+	}
+	fmt.Fprintln(p.w)
+	if marked != nil {
+		fmt.Fprint(p.w, "  ")
+	}
+	printLine(p.w, columns, headers)
+
+	for i, row := range rows {
+		if groups != nil && (i == 0 || groups[i] != groups[i-1]) {
+			size := 0
+			for _, group := range groups[i:] {
+				if group != groups[i] {
+					break
+				}
+				size++
+			}
+			fmt.Fprintf(p.w, "%s %s (%d):\n", strings.ToUpper(p.groupBy), groups[i], size)
+		}
+		cells := make([]string, len(columns))
+		for j, col := range columns {
+			cells[j] = col.value(row)
+		}
+		// -sticky-sort marks the rows whose sort value changed
+		if marked != nil && marked[i] {
+			fmt.Fprint(p.w, "* ")
+		} else if marked != nil {
+			fmt.Fprint(p.w, "  ")
+		}
+		printLine(p.w, columns, cells)
+	}
+
+	if p.sparklines != nil {
+		trend := p.sparklines.observe(atCluster(kind, p.cluster), len(rows))
+		fmt.Fprintf(p.w, "\nTotal %s: %d  %s\n", kind, len(rows), trend)
+		return
+	}
+	fmt.Fprintf(p.w, "\nTotal %s: %d\n", kind, len(rows))
+}
+
+// atCluster returns name, or name@CLUSTER in a -resource TYPE@CLUSTER
+// section. Trackers that outlive the printer key their state with it, so
+// the same kind or object in two clusters is kept apart.
+func atCluster(name, cluster string) string {
+	if cluster == "" {
+		return name
+	}
+	return name + "@" + cluster
+}
+
+// withCluster returns rows as JSON objects with a "cluster" key added.
+func withCluster[T any](rows []T, cluster string) []map[string]any {
+	objects := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		data, _ := json.Marshal(row)
+		json.Unmarshal(data, &objects[i])
+		objects[i]["cluster"] = cluster
+	}
+	return objects
+}
+
+func printLine[T any](w io.Writer, columns []column[T], cells []string) {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		padded[i] = fmt.Sprintf("%-*s", columns[i].width, cell)
+	}
+	fmt.Fprintln(w, strings.Join(padded, " "))
+}
+
+// printMarkdown writes rows as a GitHub-flavored Markdown table, for
+// pasting into tickets and incident writeups.
+func printMarkdown[T any](w io.Writer, kind string, columns []column[T], rows []T) {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+	line := func(cells []string) {
+		for i, cell := range cells {
+			cells[i] = escape.Replace(cell)
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+	}
+
+	headers := make([]string, len(columns))
+	separators := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.header
+		separators[i] = "---"
+	}
+	fmt.Fprintln(w)
+	line(headers)
+	fmt.Fprintf(w, "| %s |\n", strings.Join(separators, " | "))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for j, col := range columns {
+			cells[j] = col.value(row)
+		}
+		line(cells)
+	}
+	fmt.Fprintf(w, "\nTotal %s: %d\n", kind, len(rows))
+}
+
+// logRows appends one JSON line holding a tick's rows, so the log can be
+// processed line by line whatever the on-screen format. cluster is set for
+// a TYPE@CLUSTER section.
+func logRows[T any](w io.Writer, kind, cluster string, rows []T) {
+	if rows == nil {
+		rows = []T{}
+	}
+	data, err := json.Marshal(struct {
+		Time    time.Time `json:"time"`
+		Kind    string    `json:"kind"`
+		Cluster string    `json:"cluster,omitempty"`
+		Items   []T       `json:"items"`
+	}{time.Now(), kind, cluster, rows})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
+
+// annotationColumns shows the value of each annotation key, read from the
+// metadata the row was built from.
+func annotationColumns[T any](keys []string) []column[T] {
+	columns := make([]column[T], len(keys))
+	for i, key := range keys {
+		key := key
+		width := len(key)
+		if width < 12 {
+			width = 12
+		}
+		columns[i] = column[T]{strings.ToUpper(key), width, func(row T) string {
+			if m, ok := any(row).(interface{ objectMeta() metav1.Object }); ok && m.objectMeta() != nil {
+				if value, ok := m.objectMeta().GetAnnotations()[key]; ok {
+					return value
+				}
+			}
+			return "<none>"
+		}, nil}
+	}
+	return columns
+}
+
+// fieldName is how a column is referred to in -fields: its lowercased header.
+func fieldName[T any](col column[T]) string {
+	return strings.ToLower(col.header)
+}
+
+// selectColumns returns the named columns in the order given.
+func selectColumns[T any](columns []column[T], fields []string) ([]column[T], error) {
+	var selected []column[T]
+	for _, field := range fields {
+		found := false
+		for _, col := range columns {
+			if fieldName(col) == strings.ToLower(field) {
+				selected = append(selected, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			valid := make([]string, len(columns))
+			for i, col := range columns {
+				valid[i] = fieldName(col)
+			}
+			return nil, fmt.Errorf("unknown field %q; valid fields are: %s", field, strings.Join(valid, ", "))
+		}
+	}
+	return selected, nil
+}
+
+// parseOutput splits -output into the format name and, for
+// custom-columns, the parsed column spec.
+func parseOutput(output string) (string, []customColumn, error) {
+	if spec, ok := strings.CutPrefix(output, "custom-columns="); ok {
+		columns, err := parseCustomColumns(spec)
+		return "custom-columns", columns, err
+	}
+	if path, ok := strings.CutPrefix(output, "custom-columns-file="); ok {
+		columns, err := readCustomColumnsFile(path)
+		return "custom-columns", columns, err
+	}
+
+	switch output {
+	case "table", "json", "raw", "yaml", "markdown", "mermaid":
+		return output, nil, nil
+	}
+	return "", nil, fmt.Errorf("unsupported output format: %s", output)
+}
+
+// rowType returns the row struct rendered for a -resource name.
+func rowType(resourceType string) (reflect.Type, bool) {
+	if info, ok := lookupResource(resourceType); ok {
+		resourceType = info.name
+	}
+	switch resourceType {
+	case "pods":
+		return reflect.TypeOf(PodRow{}), true
+	case "deployments":
+		return reflect.TypeOf(DeploymentRow{}), true
+	case "services":
+		return reflect.TypeOf(ServiceRow{}), true
+	case "configmaps":
+		return reflect.TypeOf(ConfigMapRow{}), true
+	case "secrets":
+		return reflect.TypeOf(SecretRow{}), true
+	case "nodes":
+		return reflect.TypeOf(NodeRow{}), true
+	case "volumeattachments":
+		return reflect.TypeOf(VolumeAttachmentRow{}), true
+	case "csidrivers":
+		return reflect.TypeOf(CSIDriverRow{}), true
+	case "csinodes":
+		return reflect.TypeOf(CSINodeRow{}), true
+	case "replicationcontrollers":
+		return reflect.TypeOf(ReplicationControllerRow{}), true
+	case "priorityclasses":
+		return reflect.TypeOf(PriorityClassRow{}), true
+	case "componentstatuses":
+		return reflect.TypeOf(ComponentStatusRow{}), true
+	case "leases":
+		return reflect.TypeOf(LeaseRow{}), true
+	case "problems":
+		return reflect.TypeOf(ProblemRow{}), true
+	case "validatingwebhookconfigurations", "mutatingwebhookconfigurations":
+		return reflect.TypeOf(WebhookConfigRow{}), true
+	case "gateways":
+		return reflect.TypeOf(GatewayRow{}), true
+	case "httproutes":
+		return reflect.TypeOf(HTTPRouteRow{}), true
+	case "revisions":
+		return reflect.TypeOf(RevisionRow{}), true
+	case "containers":
+		return reflect.TypeOf(ContainerRow{}), true
+	case "env":
+		return reflect.TypeOf(EnvVarRow{}), true
+	case "endpoints":
+		return reflect.TypeOf(EndpointRow{}), true
+	case "overview":
+		return reflect.TypeOf(OverviewRow{}), true
+	case "used-by":
+		return reflect.TypeOf(UsedByRow{}), true
+	case "namespace-diff":
+		return reflect.TypeOf(NamespaceDiffRow{}), true
+	case "pvc-pending":
+		return reflect.TypeOf(PendingVolumeRow{}), true
+	case "evicted":
+		return reflect.TypeOf(EvictedPodRow{}), true
+	case "images":
+		return reflect.TypeOf(ImageRow{}), true
+	case "field-managers":
+		return reflect.TypeOf(FieldManagerRow{}), true
+	}
+	return nil, false
+}
+
+// printSchema writes the JSON schema of the -output json array for a
+// resource type.
+func printSchema(w io.Writer, resourceType string) error {
+	t, ok := rowType(resourceType)
+	if !ok {
+		return fmt.Errorf("unsupported resource type: %s", resourceType)
+	}
+
+	schema := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   t.Name(),
+		"type":    "array",
+		"items":   typeSchema(t),
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = typeSchema(field.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	}
+	return map[string]interface{}{}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSavedFieldsApplyToTheirKindOnly(t *testing.T) {
+	var out bytes.Buffer
+	p := &printer{w: &out, format: "table", savedFields: map[string][]string{"pods": {"name", "status"}}}
+	meta := rowMeta{&metav1.ObjectMeta{Namespace: "default", Name: "web"}}
+
+	printRows(p, "pods", nil, podColumns, []PodRow{{rowMeta: meta, Name: "web", Status: "Running"}})
+	header := strings.Fields(strings.TrimSpace(out.String()))
+	if len(header) < 2 || header[0] != "NAME" || header[1] != "STATUS" || strings.Contains(out.String(), "RESTARTS") {
+		t.Errorf("pods not limited to the saved fields:\n%s", out.String())
+	}
+
+	// -containers renders with columns the saved pods fields do not name
+	out.Reset()
+	printRows(p, "containers", nil, containerColumns, []ContainerRow{{rowMeta: meta, Pod: "web", Container: "app"}})
+	if !strings.Contains(out.String(), "CONTAINER") || !strings.Contains(out.String(), "RESTARTS") {
+		t.Errorf("containers lost columns to the saved pods fields:\n%s", out.String())
+	}
+
+	// -fields still applies to every view
+	out.Reset()
+	p.fields = []string{"pod", "container"}
+	printRows(p, "containers", nil, containerColumns, []ContainerRow{{rowMeta: meta, Pod: "web", Container: "app"}})
+	if strings.Contains(out.String(), "RESTARTS") {
+		t.Errorf("-fields not applied:\n%s", out.String())
+	}
+}
+
+func TestPrintSchema(t *testing.T) {
+	for _, tc := range []struct {
+		resourceType string
+		title        string
+	}{
+		{"pods", "PodRow"},
+		{"po", "PodRow"},
+		{"deployments", "DeploymentRow"},
+		{"services", "ServiceRow"},
+		{"configmaps", "ConfigMapRow"},
+		{"secrets", "SecretRow"},
+		{"nodes", "NodeRow"},
+		{"volumeattachments", "VolumeAttachmentRow"},
+		{"csidrivers", "CSIDriverRow"},
+		{"csinodes", "CSINodeRow"},
+		{"replicationcontrollers", "ReplicationControllerRow"},
+		{"priorityclasses", "PriorityClassRow"},
+		{"componentstatuses", "ComponentStatusRow"},
+		{"leases", "LeaseRow"},
+		{"problems", "ProblemRow"},
+		{"validatingwebhookconfigurations", "WebhookConfigRow"},
+		{"mutatingwebhookconfigurations", "WebhookConfigRow"},
+		{"gateways", "GatewayRow"},
+		{"httproutes", "HTTPRouteRow"},
+		{"revisions", "RevisionRow"},
+		{"containers", "ContainerRow"},
+		{"env", "EnvVarRow"},
+		{"endpoints", "EndpointRow"},
+		{"overview", "OverviewRow"},
+		{"used-by", "UsedByRow"},
+		{"namespace-diff", "NamespaceDiffRow"},
+		{"pvc-pending", "PendingVolumeRow"},
+		{"evicted", "EvictedPodRow"},
+		{"images", "ImageRow"},
+		{"field-managers", "FieldManagerRow"},
+	} {
+		var out bytes.Buffer
+		if err := printSchema(&out, tc.resourceType); err != nil {
+			t.Errorf("%s: %v", tc.resourceType, err)
+			continue
+		}
+		var schema struct {
+			Title string `json:"title"`
+			Items struct {
+				Properties map[string]any `json:"properties"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &schema); err != nil {
+			t.Errorf("%s: %v", tc.resourceType, err)
+			continue
+		}
+		if schema.Title != tc.title || len(schema.Items.Properties) == 0 {
+			t.Errorf("%s: got %s with %d properties, want %s", tc.resourceType, schema.Title, len(schema.Items.Properties), tc.title)
+		}
+	}
+
+	if err := printSchema(&bytes.Buffer{}, "nosuchtype"); err == nil {
+		t.Error("nosuchtype: no error")
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var replicationControllerColumns = []column[ReplicationControllerRow]{
+	{"NAME", 40, func(r ReplicationControllerRow) string { return r.Name }, nil},
+	{"DESIRED", 10, func(r ReplicationControllerRow) string { return strconv.Itoa(int(r.Desired)) }, nil},
+	{"CURRENT", 10, func(r ReplicationControllerRow) string { return strconv.Itoa(int(r.Current)) }, nil},
+	{"READY", 10, func(r ReplicationControllerRow) string { return strconv.Itoa(int(r.Ready)) }, nil},
+	{"AGE", 10, func(r ReplicationControllerRow) string { return r.Age }, ignored[ReplicationControllerRow]},
+}
+
+// listReplicationControllers lists the legacy predecessor of ReplicaSets,
+// still found in older namespaces.
+func listReplicationControllers(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	controllers, err := fetch(src, &corev1.ReplicationControllerList{}, namespace, opts, func() (*corev1.ReplicationControllerList, error) {
+		return src.clientset.CoreV1().ReplicationControllers(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []ReplicationControllerRow
+	for _, rc := range controllers.Items {
+		rows = append(rows, newReplicationControllerRow(rc))
+	}
+	printRows(p, "replicationcontrollers", controllers, replicationControllerColumns, rows)
+}
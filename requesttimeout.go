@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// timeoutTransport puts the -timeout deadline on every API request, so a
+// List cannot hang on a flaky network where TCP does not fail fast. GETs
+// carry it as timeoutSeconds for the API server to enforce as well, and
+// everything but watches is also cut off on the client. Watches stream
+// for as long as they last; the informers set their own timeoutSeconds.
+type timeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	query := req.URL.Query()
+	if req.Method == http.MethodGet && query.Get("timeoutSeconds") == "" {
+		query.Set("timeoutSeconds", strconv.Itoa(int(math.Ceil(t.timeout.Seconds()))))
+		req = req.Clone(req.Context())
+		req.URL.RawQuery = query.Encode()
+	}
+	if watch := query.Get("watch"); watch == "true" || watch == "1" {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the deadline covers reading the body too
+	resp.Body = &cancelOnClose{resp.Body, cancel}
+	return resp, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// resourceInfo describes a -resource name this tool knows about.
+type resourceInfo struct {
+	name       string // canonical plural name, as used in the switches on -resource
+	aliases    []string
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+// resourceRegistry lists the known resource types. Cluster-scoped ones
+// ignore -namespace. Resources without a typed listing, such as
+// clusterroles, are still listed through the dynamic client.
+var resourceRegistry = []resourceInfo{
+	{"pods", []string{"pod", "po"}, corev1.SchemeGroupVersion.WithResource("pods"), true},
+	{"deployments", []string{"deployment", "deploy"}, appsv1.SchemeGroupVersion.WithResource("deployments"), true},
+	{"services", []string{"service", "svc"}, corev1.SchemeGroupVersion.WithResource("services"), true},
+	{"configmaps", []string{"configmap", "cm"}, corev1.SchemeGroupVersion.WithResource("configmaps"), true},
+	{"secrets", []string{"secret"}, corev1.SchemeGroupVersion.WithResource("secrets"), true},
+	{"replicationcontrollers", []string{"replicationcontroller", "rc"}, corev1.SchemeGroupVersion.WithResource("replicationcontrollers"), true},
+	{"leases", []string{"lease"}, coordinationv1.SchemeGroupVersion.WithResource("leases"), true},
+	{"nodes", []string{"node", "no"}, corev1.SchemeGroupVersion.WithResource("nodes"), false},
+	{"volumeattachments", []string{"volumeattachment"}, storagev1.SchemeGroupVersion.WithResource("volumeattachments"), false},
+	{"persistentvolumes", []string{"persistentvolume", "pv"}, corev1.SchemeGroupVersion.WithResource("persistentvolumes"), false},
+	{"namespaces", []string{"namespace", "ns"}, corev1.SchemeGroupVersion.WithResource("namespaces"), false},
+	{"storageclasses", []string{"storageclass", "sc"}, storagev1.SchemeGroupVersion.WithResource("storageclasses"), false},
+	{"validatingwebhookconfigurations", []string{"validatingwebhookconfiguration"}, admissionregistrationv1.SchemeGroupVersion.WithResource("validatingwebhookconfigurations"), false},
+	{"mutatingwebhookconfigurations", []string{"mutatingwebhookconfiguration"}, admissionregistrationv1.SchemeGroupVersion.WithResource("mutatingwebhookconfigurations"), false},
+	{"priorityclasses", []string{"priorityclass", "pc"}, schedulingv1.SchemeGroupVersion.WithResource("priorityclasses"), false},
+	{"csidrivers", []string{"csidriver"}, storagev1.SchemeGroupVersion.WithResource("csidrivers"), false},
+	{"csinodes", []string{"csinode"}, storagev1.SchemeGroupVersion.WithResource("csinodes"), false},
+	{"componentstatuses", []string{"componentstatus", "cs"}, corev1.SchemeGroupVersion.WithResource("componentstatuses"), false},
+	{"clusterroles", []string{"clusterrole"}, rbacv1.SchemeGroupVersion.WithResource("clusterroles"), false},
+
+	// kubectl's other built-in short names, listed through the dynamic
+	// client
+	{"persistentvolumeclaims", []string{"persistentvolumeclaim", "pvc"}, corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims"), true},
+	{"serviceaccounts", []string{"serviceaccount", "sa"}, corev1.SchemeGroupVersion.WithResource("serviceaccounts"), true},
+	{"endpoints", []string{"ep"}, corev1.SchemeGroupVersion.WithResource("endpoints"), true},
+	{"events", []string{"event", "ev"}, corev1.SchemeGroupVersion.WithResource("events"), true},
+	{"limitranges", []string{"limitrange", "limits"}, corev1.SchemeGroupVersion.WithResource("limitranges"), true},
+	{"resourcequotas", []string{"resourcequota", "quota"}, corev1.SchemeGroupVersion.WithResource("resourcequotas"), true},
+	{"daemonsets", []string{"daemonset", "ds"}, appsv1.SchemeGroupVersion.WithResource("daemonsets"), true},
+	{"statefulsets", []string{"statefulset", "sts"}, appsv1.SchemeGroupVersion.WithResource("statefulsets"), true},
+	{"replicasets", []string{"replicaset", "rs"}, appsv1.SchemeGroupVersion.WithResource("replicasets"), true},
+	{"jobs", []string{"job"}, batchv1.SchemeGroupVersion.WithResource("jobs"), true},
+	{"cronjobs", []string{"cronjob", "cj"}, batchv1.SchemeGroupVersion.WithResource("cronjobs"), true},
+	{"horizontalpodautoscalers", []string{"horizontalpodautoscaler", "hpa"}, autoscalingv2.SchemeGroupVersion.WithResource("horizontalpodautoscalers"), true},
+	{"ingresses", []string{"ingress", "ing"}, networkingv1.SchemeGroupVersion.WithResource("ingresses"), true},
+	{"networkpolicies", []string{"networkpolicy", "netpol"}, networkingv1.SchemeGroupVersion.WithResource("networkpolicies"), true},
+	{"poddisruptionbudgets", []string{"poddisruptionbudget", "pdb"}, policyv1.SchemeGroupVersion.WithResource("poddisruptionbudgets"), true},
+	{"certificatesigningrequests", []string{"certificatesigningrequest", "csr"}, certificatesv1.SchemeGroupVersion.WithResource("certificatesigningrequests"), false},
+	{"customresourcedefinitions", []string{"customresourcedefinition", "crd", "crds"}, schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}, false},
+
+	// Gateway API CRDs, listed through the dynamic client when installed
+	{"gateways", []string{"gateway", "gtw"}, schema.GroupVersionResource{Group: gatewayGroup, Version: "v1", Resource: "gateways"}, true},
+	{"httproutes", []string{"httproute"}, schema.GroupVersionResource{Group: gatewayGroup, Version: "v1", Resource: "httproutes"}, true},
+}
+
+// lookupResource finds a resource by its name or one of its aliases.
+func lookupResource(name string) (resourceInfo, bool) {
+	for _, info := range resourceRegistry {
+		if info.name == name {
+			return info, true
+		}
+		for _, alias := range info.aliases {
+			if alias == name {
+				return info, true
+			}
+		}
+	}
+	return resourceInfo{}, false
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// restartRateTracker keeps each pod's restart counts over the last window
+// of a watch, for the RESTARTS/MIN column: a pod that crashed a lot but
+// has settled reads ~0, one crashing right now reads high. It outlives the
+// per-tick printer.
+type restartRateTracker struct {
+	window  time.Duration
+	samples map[string][]restartSample // the window, oldest first
+}
+
+func newRestartRateTracker(window time.Duration) *restartRateTracker {
+	return &restartRateTracker{window: window, samples: map[string][]restartSample{}}
+}
+
+// observe records this tick's restart counts and returns each pod's
+// restarts per minute over the window, keyed by namespace/name. Pods seen
+// for the first time have no rate yet. cluster keeps the pods of a
+// -resource pods@CLUSTER section apart.
+func (t *restartRateTracker) observe(cluster string, pods []corev1.Pod) map[string]float64 {
+	now := time.Now()
+	rates := map[string]float64{}
+	for _, pod := range pods {
+		key := cluster + "/" + pod.Namespace + "/" + pod.Name
+		sample := restartSample{now, getTotalRestarts(pod.Status.ContainerStatuses)}
+		samples := t.samples[key]
+		if len(samples) > 0 && sample.restarts < samples[len(samples)-1].restarts {
+			// recreated under the same name
+			samples = nil
+		}
+		samples = append(samples, sample)
+		for len(samples) > 1 && now.Sub(samples[0].at) > t.window {
+			samples = samples[1:]
+		}
+		t.samples[key] = samples
+
+		if elapsed := now.Sub(samples[0].at); len(samples) > 1 && elapsed > 0 {
+			rates[pod.Namespace+"/"+pod.Name] = float64(sample.restarts-samples[0].restarts) / elapsed.Minutes()
+		}
+	}
+
+	// forget pods gone for longer than the window
+	for key, samples := range t.samples {
+		if now.Sub(samples[len(samples)-1].at) > t.window {
+			delete(t.samples, key)
+		}
+	}
+	return rates
+}
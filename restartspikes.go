@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// restartSample is a pod's total restart count at one tick.
+type restartSample struct {
+	at       time.Time
+	restarts int
+}
+
+// restartSpikeDetector baselines each pod's restart rate over the watch
+// session for -detect-restart-spikes, and reports a pod when its restarts
+// within window exceed factor times what the baseline predicts. It
+// outlives the per-tick printer.
+type restartSpikeDetector struct {
+	window      time.Duration
+	factor      float64
+	minRestarts int                        // smallest jump worth reporting
+	first       map[string]restartSample   // where the baseline starts
+	samples     map[string][]restartSample // the window, and the sample just before it
+	spiking     map[string]bool
+}
+
+func newRestartSpikeDetector(window time.Duration, factor float64, minRestarts int) *restartSpikeDetector {
+	return &restartSpikeDetector{
+		window:      window,
+		factor:      factor,
+		minRestarts: minRestarts,
+		first:       map[string]restartSample{},
+		samples:     map[string][]restartSample{},
+		spiking:     map[string]bool{},
+	}
+}
+
+// observe records this tick's restart counts and prints the pods whose
+// restarts just started spiking. A pod is reported again only after it
+// has calmed down. cluster keeps the pods of a -resource pods@CLUSTER
+// section apart.
+func (d *restartSpikeDetector) observe(w io.Writer, cluster string, pods []corev1.Pod) {
+	now := time.Now()
+	var spikes []string
+	for _, pod := range pods {
+		key := atCluster(pod.Namespace+"/"+pod.Name, cluster)
+		sample := restartSample{now, getTotalRestarts(pod.Status.ContainerStatuses)}
+		first, seen := d.first[key]
+		if !seen || sample.restarts < first.restarts {
+			// new, or recreated under the same name
+			d.first[key] = sample
+			d.samples[key] = []restartSample{sample}
+			d.spiking[key] = false
+			continue
+		}
+
+		samples := append(d.samples[key], sample)
+		for len(samples) > 1 && now.Sub(samples[1].at) > d.window {
+			samples = samples[1:]
+		}
+		d.samples[key] = samples
+
+		start := samples[0]
+		delta := sample.restarts - start.restarts
+		baseline := 0.0
+		if elapsed := start.at.Sub(first.at); elapsed > 0 {
+			baseline = float64(start.restarts-first.restarts) / elapsed.Seconds() * d.window.Seconds()
+		}
+
+		spiking := delta >= d.minRestarts && float64(delta) > d.factor*baseline
+		if spiking && !d.spiking[key] {
+			spikes = append(spikes, fmt.Sprintf("RESTART SPIKE pod %s: +%d restarts in the last %s (baseline %.1f per %s)",
+				key, delta, d.window, baseline, d.window))
+		}
+		d.spiking[key] = spiking
+	}
+
+	sort.Strings(spikes)
+	for _, spike := range spikes {
+		fmt.Fprintln(w, spike)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// deploymentRolloutColumns replace the deployment columns with -rollout.
+var deploymentRolloutColumns = []column[DeploymentRow]{
+	{"NAME", 40, func(r DeploymentRow) string { return r.Name }, nil},
+	{"GAP", 45, func(r DeploymentRow) string {
+		return fmt.Sprintf("desired=%d ready=%d updated=%d unavailable=%d", r.Desired, r.Rollout.Ready, r.UpToDate, r.Rollout.Unavailable)
+	}, nil},
+	{"ROLLOUT", 0, func(r DeploymentRow) string {
+		if r.Rollout.Detail == "" {
+			return r.Rollout.State
+		}
+		return r.Rollout.State + " (" + r.Rollout.Detail + ")"
+	}, nil},
+}
+
+// rolloutTracker follows each deployment's progress across watch ticks for
+// -rollout, to estimate completion and notice stalls. It outlives the
+// per-tick printer.
+type rolloutTracker struct {
+	stallTimeout time.Duration
+	progress     map[types.UID]rolloutProgress
+}
+
+type rolloutProgress struct {
+	generation int64
+	first      int32 // progress when this generation was first seen
+	firstSeen  time.Time
+	last       int32
+	lastChange time.Time
+}
+
+func newRolloutTracker(stallTimeout time.Duration) *rolloutTracker {
+	return &rolloutTracker{stallTimeout: stallTimeout, progress: map[types.UID]rolloutProgress{}}
+}
+
+// observe returns the deployment's rollout status. Progress counts updated
+// plus available replicas out of twice the desired count; the estimate
+// extrapolates the rate seen since this generation was first observed.
+func (t *rolloutTracker) observe(deployment appsv1.Deployment) *RolloutStatus {
+	status := &RolloutStatus{
+		Ready:       deployment.Status.ReadyReplicas,
+		Unavailable: deployment.Status.UnavailableReplicas,
+	}
+	desired := getDesiredReplicas(deployment)
+	target := 2 * desired
+	current := deployment.Status.UpdatedReplicas + deployment.Status.AvailableReplicas
+	now := time.Now()
+
+	p, ok := t.progress[deployment.UID]
+	if !ok || p.generation != deployment.Generation {
+		p = rolloutProgress{generation: deployment.Generation, first: current, firstSeen: now, last: current, lastChange: now}
+	}
+	if current != p.last {
+		p.last = current
+		p.lastChange = now
+	}
+	t.progress[deployment.UID] = p
+
+	complete := deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == desired &&
+		deployment.Status.AvailableReplicas == desired &&
+		deployment.Status.UnavailableReplicas == 0
+	switch {
+	case complete:
+		status.State = "complete"
+	case progressDeadlineExceeded(deployment):
+		status.State = "stalled"
+		status.Detail = "progress deadline exceeded"
+	case now.Sub(p.lastChange) >= t.stallTimeout:
+		status.State = "stalled"
+		status.Detail = fmt.Sprintf("no progress for %s", now.Sub(p.lastChange).Round(time.Second))
+	default:
+		status.State = "progressing"
+		if elapsed := now.Sub(p.firstSeen); current > p.first && elapsed > 0 {
+			rate := float64(current-p.first) / elapsed.Seconds()
+			eta := time.Duration(float64(target-current) / rate * float64(time.Second))
+			status.Detail = fmt.Sprintf("eta %s", eta.Round(time.Second))
+		}
+	}
+	return status
+}
+
+func progressDeadlineExceeded(deployment appsv1.Deployment) bool {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse && condition.Reason == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
+
+// deploymentConditionProblem returns the reason and message of the
+// condition explaining why a deployment is degraded, most specific first:
+// a ReplicaFailure (such as a quota rejecting new pods), Progressing turned
+// false (ProgressDeadlineExceeded), then Available turned false. It
+// returns "" when no condition says anything is wrong.
+func deploymentConditionProblem(deployment appsv1.Deployment) string {
+	checks := []struct {
+		kind   appsv1.DeploymentConditionType
+		status corev1.ConditionStatus
+	}{
+		{appsv1.DeploymentReplicaFailure, corev1.ConditionTrue},
+		{appsv1.DeploymentProgressing, corev1.ConditionFalse},
+		{appsv1.DeploymentAvailable, corev1.ConditionFalse},
+	}
+	for _, check := range checks {
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == check.kind && condition.Status == check.status {
+				return fmt.Sprintf("%s: %s", condition.Reason, condition.Message)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,686 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The row types below are what every renderer is built from. Their JSON
+// form is the contract for -output json; see -print-schema.
+
+// rowMeta carries the source object's metadata so renderers can add
+// columns beyond the row's own fields, such as -show-annotations. It is
+// unexported and never serialized.
+type rowMeta struct {
+	meta metav1.Object
+}
+
+func (r rowMeta) objectMeta() metav1.Object { return r.meta }
+
+// PodRow is one line of the pods listing.
+type PodRow struct {
+	rowMeta
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Ready     string    `json:"ready"`
+	Restarts  int       `json:"restarts"`
+	IP        string    `json:"ip"`
+	Node      string    `json:"node"`
+	QOSClass  string    `json:"qosClass"`
+	Created   time.Time `json:"created"`
+	Age       string    `json:"age"`
+	// Zone is only filled in with -wide, -fields or -group-by
+	Zone string `json:"zone,omitempty"`
+	// Explanation is only filled in with -explain
+	Explanation string `json:"explanation,omitempty"`
+	// RestartsPerMinute is only set in watch mode, once a pod has been
+	// seen on two ticks
+	RestartsPerMinute *float64 `json:"restartsPerMinute,omitempty"`
+}
+
+// DeploymentRow is one line of the deployments listing.
+type DeploymentRow struct {
+	rowMeta
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Ready     string    `json:"ready"`
+	Desired   int32     `json:"desired"`
+	UpToDate  int32     `json:"upToDate"`
+	Available int32     `json:"available"`
+	Created   time.Time `json:"created"`
+	Age       string    `json:"age"`
+	// ChangeCause is the kubernetes.io/change-cause of the last rollout
+	ChangeCause string `json:"changeCause,omitempty"`
+	// Rollout is only filled in with -rollout
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+}
+
+// RolloutStatus is the gap between a deployment's desired and actual
+// state: State is "complete", "progressing" or "stalled", with an estimate
+// or the stall reason in Detail.
+type RolloutStatus struct {
+	Ready       int32  `json:"ready"`
+	Unavailable int32  `json:"unavailable"`
+	State       string `json:"state"`
+	Detail      string `json:"detail,omitempty"`
+}
+
+// ServiceRow is one line of the services listing.
+type ServiceRow struct {
+	rowMeta
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	ClusterIP  string    `json:"clusterIP"`
+	ExternalIP string    `json:"externalIP"`
+	Ports      []string  `json:"ports"`
+	Created    time.Time `json:"created"`
+	Age        string    `json:"age"`
+}
+
+// ConfigMapRow is one line of the configmaps listing.
+type ConfigMapRow struct {
+	rowMeta
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Data      int       `json:"data"`
+	Created   time.Time `json:"created"`
+	Age       string    `json:"age"`
+}
+
+// SecretRow is one line of the secrets listing. Secret values are never
+// included, only how many keys there are.
+type SecretRow struct {
+	rowMeta
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Data      int       `json:"data"`
+	Created   time.Time `json:"created"`
+	Age       string    `json:"age"`
+}
+
+// NodeRow is one line of the nodes listing.
+type NodeRow struct {
+	rowMeta
+	Name       string    `json:"name"`
+	Status     string    `json:"status"`
+	Roles      string    `json:"roles"`
+	Version    string    `json:"version"`
+	Zone       string    `json:"zone"`
+	Conditions []string  `json:"conditions"`
+	Created    time.Time `json:"created"`
+	Age        string    `json:"age"`
+}
+
+// VolumeAttachmentRow is one line of the volumeattachments listing.
+type VolumeAttachmentRow struct {
+	rowMeta
+	Name     string    `json:"name"`
+	Attacher string    `json:"attacher"`
+	PV       string    `json:"pv"`
+	Node     string    `json:"node"`
+	Attached bool      `json:"attached"`
+	Created  time.Time `json:"created"`
+	Age      string    `json:"age"`
+}
+
+// CSIDriverRow is one line of the csidrivers listing.
+type CSIDriverRow struct {
+	rowMeta
+	Name            string    `json:"name"`
+	AttachRequired  bool      `json:"attachRequired"`
+	PodInfoOnMount  bool      `json:"podInfoOnMount"`
+	StorageCapacity bool      `json:"storageCapacity"`
+	Modes           []string  `json:"modes"`
+	Created         time.Time `json:"created"`
+	Age             string    `json:"age"`
+}
+
+// CSINodeRow is one driver registered on a node in the csinodes listing.
+// MaxVolumes is nil when the driver reports no limit.
+type CSINodeRow struct {
+	rowMeta
+	Node       string    `json:"node"`
+	Driver     string    `json:"driver"`
+	NodeID     string    `json:"nodeID"`
+	MaxVolumes *int32    `json:"maxVolumes,omitempty"`
+	Created    time.Time `json:"created"`
+	Age        string    `json:"age"`
+}
+
+// ReplicationControllerRow is one line of the replicationcontrollers
+// listing.
+type ReplicationControllerRow struct {
+	rowMeta
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Desired   int32     `json:"desired"`
+	Current   int32     `json:"current"`
+	Ready     int32     `json:"ready"`
+	Created   time.Time `json:"created"`
+	Age       string    `json:"age"`
+}
+
+// LeaseRow is one line of the leases listing. RenewTime is zero for a
+// lease that was never renewed.
+type LeaseRow struct {
+	rowMeta
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Holder    string    `json:"holder"`
+	RenewTime time.Time `json:"renewTime"`
+	Stale     bool      `json:"stale"`
+	Created   time.Time `json:"created"`
+	Age       string    `json:"age"`
+}
+
+// ProblemRow is one unhealthy object in the -only-problems view, with the
+// latest Warning event about it.
+type ProblemRow struct {
+	Kind      string        `json:"kind"`
+	Namespace string        `json:"namespace,omitempty"`
+	Name      string        `json:"name"`
+	Problem   string        `json:"problem"`
+	Event     *WarningEvent `json:"event"`
+}
+
+// WarningEvent is the gist of a Warning event.
+type WarningEvent struct {
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// WebhookConfigRow is one line of the validatingwebhookconfigurations and
+// mutatingwebhookconfigurations listings. FailurePolicies and Targets are
+// the distinct values across its webhooks.
+type WebhookConfigRow struct {
+	rowMeta
+	Name            string    `json:"name"`
+	Webhooks        int       `json:"webhooks"`
+	FailurePolicies []string  `json:"failurePolicies"`
+	Targets         []string  `json:"targets"`
+	Created         time.Time `json:"created"`
+	Age             string    `json:"age"`
+}
+
+// PriorityClassRow is one line of the priorityclasses listing.
+type PriorityClassRow struct {
+	rowMeta
+	Name             string    `json:"name"`
+	Value            int32     `json:"value"`
+	GlobalDefault    bool      `json:"globalDefault"`
+	PreemptionPolicy string    `json:"preemptionPolicy"`
+	Created          time.Time `json:"created"`
+	Age              string    `json:"age"`
+}
+
+// ComponentStatusRow is one line of the componentstatuses listing. Status
+// is "Healthy" or "Unhealthy"; Message is the component's message or
+// error.
+type ComponentStatusRow struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// ContainerRow is one container of a pod in the -containers listing. Type
+// is "init", "regular" or "ephemeral"; Target is the container an
+// ephemeral (debug) container was attached to.
+type ContainerRow struct {
+	rowMeta
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Type      string `json:"type"`
+	Target    string `json:"target,omitempty"`
+	// InjectedBy names the mutating webhook, such as istio or linkerd,
+	// that most likely added the container to the pod
+	InjectedBy string `json:"injectedBy,omitempty"`
+	Image      string `json:"image"`
+	State      string `json:"state"`
+	Ready      bool   `json:"ready"`
+	Restarts   int32  `json:"restarts"`
+	// LastTermination is how the previous run of a restarted container
+	// ended
+	LastTermination *Termination `json:"lastTermination,omitempty"`
+}
+
+// Termination is how a container run ended.
+type Termination struct {
+	Reason     string    `json:"reason"`
+	ExitCode   int32     `json:"exitCode"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// EnvVarRow is one environment variable of a container in the -show-env
+// listing. Source is "literal", "configMapKeyRef", "secretKeyRef",
+// "fieldRef" or "resourceFieldRef", or "configMapRef" or "secretRef" for
+// an envFrom, whose Name is its prefix followed by "*". From says which
+// object and key, field or resource the value comes from; the value
+// itself is never included.
+type EnvVarRow struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Name      string `json:"name"`
+	Source    string `json:"source"`
+	From      string `json:"from,omitempty"`
+}
+
+// RevisionRow is one revision in a deployment's -history. Current marks
+// the revision the deployment is at.
+type RevisionRow struct {
+	rowMeta
+	Revision    int64     `json:"revision"`
+	Current     bool      `json:"current"`
+	ReplicaSet  string    `json:"replicaSet"`
+	Images      []string  `json:"images"`
+	ChangeCause string    `json:"changeCause,omitempty"`
+	Created     time.Time `json:"created"`
+	Age         string    `json:"age"`
+}
+
+// EndpointRow is one pod (or bare address) behind a service in the
+// -endpoints drill-down.
+type EndpointRow struct {
+	Pod      string `json:"pod"`
+	Ready    string `json:"ready"`
+	IP       string `json:"ip"`
+	Node     string `json:"node"`
+	Endpoint string `json:"endpoint"`
+	// Serving and Terminating are the endpoint's conditions, "-" for a
+	// pod without one
+	Serving     string `json:"serving"`
+	Terminating string `json:"terminating"`
+}
+
+// GatewayRow is one line of the gateways listing. Programmed is the
+// status of the Gateway's Programmed condition, "Unknown" until the
+// controller sets it.
+type GatewayRow struct {
+	rowMeta
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Class      string    `json:"class"`
+	Addresses  []string  `json:"addresses"`
+	Listeners  []string  `json:"listeners"`
+	Programmed string    `json:"programmed"`
+	Created    time.Time `json:"created"`
+	Age        string    `json:"age"`
+}
+
+// HTTPRouteRow is one line of the httproutes listing. Parents are the
+// Gateways it attaches to and Backends the objects its rules send
+// traffic to, as namespace/name.
+type HTTPRouteRow struct {
+	rowMeta
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Hostnames []string  `json:"hostnames"`
+	Parents   []string  `json:"parents"`
+	Backends  []string  `json:"backends"`
+	Created   time.Time `json:"created"`
+	Age       string    `json:"age"`
+}
+
+// ImageRow is one container image in the images report.
+type ImageRow struct {
+	Image      string   `json:"image"`
+	Pods       int      `json:"pods"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// PendingVolumeRow is a Pending pod's claim that is not Bound, in the
+// pvc-pending report. Status is the claim's phase, or Missing.
+type PendingVolumeRow struct {
+	Namespace    string `json:"namespace"`
+	Pod          string `json:"pod"`
+	PVC          string `json:"pvc"`
+	Status       string `json:"status"`
+	StorageClass string `json:"storageClass"`
+}
+
+// EvictedPodRow is one pod in the -cleanup-evicted listing.
+type EvictedPodRow struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Node      string    `json:"node"`
+	Message   string    `json:"message"`
+	EvictedAt time.Time `json:"evictedAt"`
+	Age       string    `json:"age"`
+}
+
+// FieldManagerRow is one manager of an object in the -field-managers
+// listing, with the fields it owns as dotted paths.
+type FieldManagerRow struct {
+	Manager     string    `json:"manager"`
+	Operation   string    `json:"operation"`
+	Subresource string    `json:"subresource,omitempty"`
+	Time        time.Time `json:"time"`
+	Fields      []string  `json:"fields"`
+}
+
+// NamespaceDiffRow is one difference in the -diff-namespace report: a
+// field that differs between the two namespaces' objects of the same
+// name, or Field "object" for an object only one of them has.
+type NamespaceDiffRow struct {
+	Name  string `json:"name"`
+	Field string `json:"field"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+// UsedByRow is one reference from a pod to a ConfigMap or Secret in the
+// -used-by report. Via is "volume", "projected volume", "envFrom", "env"
+// or "imagePullSecrets"; Updates says when the pod sees a change to the
+// object: "live", "on restart", "next pull", or "-" for a volume no
+// container mounts.
+type UsedByRow struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"`
+	Via       string `json:"via"`
+	Updates   string `json:"updates"`
+	Detail    string `json:"detail"`
+}
+
+// OverviewRow is one namespace in the overview.
+type OverviewRow struct {
+	Namespace            string   `json:"namespace"`
+	RunningPods          int      `json:"runningPods"`
+	TotalPods            int      `json:"totalPods"`
+	AvailableDeployments int      `json:"availableDeployments"`
+	TotalDeployments     int      `json:"totalDeployments"`
+	QuotaWarnings        []string `json:"quotaWarnings"`
+	Problems             []string `json:"problems"`
+}
+
+func newPodRow(pod corev1.Pod) PodRow {
+	return PodRow{
+		rowMeta:   rowMeta{&pod.ObjectMeta},
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		Status:    getPodStatus(pod),
+		Ready:     fmt.Sprintf("%d/%d", getReadyContainers(pod.Status.ContainerStatuses), len(pod.Spec.Containers)),
+		Restarts:  getTotalRestarts(pod.Status.ContainerStatuses),
+		IP:        pod.Status.PodIP,
+		Node:      pod.Spec.NodeName,
+		QOSClass:  string(getQOSClass(pod)),
+		Created:   pod.CreationTimestamp.Time,
+		Age:       formatAge(pod.CreationTimestamp.Time),
+	}
+}
+
+func newDeploymentRow(deployment appsv1.Deployment) DeploymentRow {
+	desired := getDesiredReplicas(deployment)
+	return DeploymentRow{
+		rowMeta:     rowMeta{&deployment.ObjectMeta},
+		Namespace:   deployment.Namespace,
+		Name:        deployment.Name,
+		Ready:       fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, desired),
+		Desired:     desired,
+		UpToDate:    deployment.Status.UpdatedReplicas,
+		Available:   deployment.Status.AvailableReplicas,
+		Created:     deployment.CreationTimestamp.Time,
+		Age:         formatAge(deployment.CreationTimestamp.Time),
+		ChangeCause: deployment.Annotations[changeCauseAnnotation],
+	}
+}
+
+func newServiceRow(svc corev1.Service) ServiceRow {
+	externalIP := "<none>"
+	if len(svc.Status.LoadBalancer.Ingress) > 0 {
+		externalIP = svc.Status.LoadBalancer.Ingress[0].IP
+		if externalIP == "" && svc.Status.LoadBalancer.Ingress[0].Hostname != "" {
+			externalIP = svc.Status.LoadBalancer.Ingress[0].Hostname
+		}
+	}
+
+	var ports []string
+	for _, port := range svc.Spec.Ports {
+		// an unset targetPort defaults to the port itself
+		target := port.TargetPort.String()
+		if port.TargetPort.IntValue() == 0 && port.TargetPort.StrVal == "" {
+			target = strconv.Itoa(int(port.Port))
+		}
+		entry := fmt.Sprintf("%d:%s/%s", port.Port, target, port.Protocol)
+		if port.NodePort != 0 {
+			entry += fmt.Sprintf(" (node %d)", port.NodePort)
+		}
+		ports = append(ports, entry)
+	}
+
+	return ServiceRow{
+		rowMeta:    rowMeta{&svc.ObjectMeta},
+		Namespace:  svc.Namespace,
+		Name:       svc.Name,
+		Type:       string(svc.Spec.Type),
+		ClusterIP:  svc.Spec.ClusterIP,
+		ExternalIP: externalIP,
+		Ports:      ports,
+		Created:    svc.CreationTimestamp.Time,
+		Age:        formatAge(svc.CreationTimestamp.Time),
+	}
+}
+
+func newConfigMapRow(cm corev1.ConfigMap) ConfigMapRow {
+	return ConfigMapRow{
+		rowMeta:   rowMeta{&cm.ObjectMeta},
+		Namespace: cm.Namespace,
+		Name:      cm.Name,
+		Data:      len(cm.Data),
+		Created:   cm.CreationTimestamp.Time,
+		Age:       formatAge(cm.CreationTimestamp.Time),
+	}
+}
+
+func newSecretRow(secret corev1.Secret) SecretRow {
+	return SecretRow{
+		rowMeta:   rowMeta{&secret.ObjectMeta},
+		Namespace: secret.Namespace,
+		Name:      secret.Name,
+		Type:      string(secret.Type),
+		Data:      len(secret.Data),
+		Created:   secret.CreationTimestamp.Time,
+		Age:       formatAge(secret.CreationTimestamp.Time),
+	}
+}
+
+func newNodeRow(node corev1.Node) NodeRow {
+	roles := "<none>"
+	if val, ok := node.Labels["kubernetes.io/role"]; ok {
+		roles = val
+	} else if val, ok := node.Labels["node-role.kubernetes.io/master"]; ok && val == "true" {
+		roles = "master"
+	} else if val, ok := node.Labels["node-role.kubernetes.io/control-plane"]; ok && val == "true" {
+		roles = "control-plane"
+	}
+
+	return NodeRow{
+		rowMeta:    rowMeta{&node.ObjectMeta},
+		Name:       node.Name,
+		Status:     getNodeStatus(node),
+		Roles:      roles,
+		Version:    node.Status.NodeInfo.KubeletVersion,
+		Zone:       nodeZone(node),
+		Conditions: getNodeProblems(node.Status.Conditions),
+		Created:    node.CreationTimestamp.Time,
+		Age:        formatAge(node.CreationTimestamp.Time),
+	}
+}
+
+func newVolumeAttachmentRow(va storagev1.VolumeAttachment) VolumeAttachmentRow {
+	pv := "<inline>"
+	if va.Spec.Source.PersistentVolumeName != nil {
+		pv = *va.Spec.Source.PersistentVolumeName
+	}
+
+	return VolumeAttachmentRow{
+		rowMeta:  rowMeta{&va.ObjectMeta},
+		Name:     va.Name,
+		Attacher: va.Spec.Attacher,
+		PV:       pv,
+		Node:     va.Spec.NodeName,
+		Attached: va.Status.Attached,
+		Created:  va.CreationTimestamp.Time,
+		Age:      formatAge(va.CreationTimestamp.Time),
+	}
+}
+
+func newCSIDriverRow(driver storagev1.CSIDriver) CSIDriverRow {
+	// unset fields are what the API server defaults them to
+	row := CSIDriverRow{
+		rowMeta:        rowMeta{&driver.ObjectMeta},
+		Name:           driver.Name,
+		AttachRequired: driver.Spec.AttachRequired == nil || *driver.Spec.AttachRequired,
+		PodInfoOnMount: driver.Spec.PodInfoOnMount != nil && *driver.Spec.PodInfoOnMount,
+		Created:        driver.CreationTimestamp.Time,
+		Age:            formatAge(driver.CreationTimestamp.Time),
+	}
+	if driver.Spec.StorageCapacity != nil {
+		row.StorageCapacity = *driver.Spec.StorageCapacity
+	}
+	for _, mode := range driver.Spec.VolumeLifecycleModes {
+		row.Modes = append(row.Modes, string(mode))
+	}
+	return row
+}
+
+func newCSINodeRows(csiNode storagev1.CSINode) []CSINodeRow {
+	base := CSINodeRow{
+		rowMeta: rowMeta{&csiNode.ObjectMeta},
+		Node:    csiNode.Name,
+		Created: csiNode.CreationTimestamp.Time,
+		Age:     formatAge(csiNode.CreationTimestamp.Time),
+	}
+	if len(csiNode.Spec.Drivers) == 0 {
+		return []CSINodeRow{base}
+	}
+	var rows []CSINodeRow
+	for _, driver := range csiNode.Spec.Drivers {
+		row := base
+		row.Driver, row.NodeID = driver.Name, driver.NodeID
+		if driver.Allocatable != nil {
+			row.MaxVolumes = driver.Allocatable.Count
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func newReplicationControllerRow(rc corev1.ReplicationController) ReplicationControllerRow {
+	desired := int32(1)
+	if rc.Spec.Replicas != nil {
+		desired = *rc.Spec.Replicas
+	}
+
+	return ReplicationControllerRow{
+		rowMeta:   rowMeta{&rc.ObjectMeta},
+		Namespace: rc.Namespace,
+		Name:      rc.Name,
+		Desired:   desired,
+		Current:   rc.Status.Replicas,
+		Ready:     rc.Status.ReadyReplicas,
+		Created:   rc.CreationTimestamp.Time,
+		Age:       formatAge(rc.CreationTimestamp.Time),
+	}
+}
+
+func newLeaseRow(lease coordinationv1.Lease) LeaseRow {
+	row := LeaseRow{
+		rowMeta:   rowMeta{&lease.ObjectMeta},
+		Namespace: lease.Namespace,
+		Name:      lease.Name,
+		Stale:     leaseStale(lease),
+		Created:   lease.CreationTimestamp.Time,
+		Age:       formatAge(lease.CreationTimestamp.Time),
+	}
+	if lease.Spec.HolderIdentity != nil {
+		row.Holder = *lease.Spec.HolderIdentity
+	}
+	if lease.Spec.RenewTime != nil {
+		row.RenewTime = lease.Spec.RenewTime.Time
+	}
+	return row
+}
+
+func newPriorityClassRow(class schedulingv1.PriorityClass) PriorityClassRow {
+	// the API server defaults an unset policy to preempting
+	policy := string(corev1.PreemptLowerPriority)
+	if class.PreemptionPolicy != nil {
+		policy = string(*class.PreemptionPolicy)
+	}
+	return PriorityClassRow{
+		rowMeta:          rowMeta{&class.ObjectMeta},
+		Name:             class.Name,
+		Value:            class.Value,
+		GlobalDefault:    class.GlobalDefault,
+		PreemptionPolicy: policy,
+		Created:          class.CreationTimestamp.Time,
+		Age:              formatAge(class.CreationTimestamp.Time),
+	}
+}
+
+func newComponentStatusRow(status corev1.ComponentStatus) ComponentStatusRow {
+	row := ComponentStatusRow{Name: status.Name, Status: "Unhealthy"}
+	for _, condition := range status.Conditions {
+		if condition.Type != corev1.ComponentHealthy {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			row.Status = "Healthy"
+		}
+		row.Message = condition.Message
+		if condition.Error != "" {
+			row.Message = condition.Error
+		}
+	}
+	return row
+}
+
+// newWebhookConfigRow starts a row; its webhooks are added with
+// addWebhook.
+func newWebhookConfigRow(meta metav1.ObjectMeta) WebhookConfigRow {
+	return WebhookConfigRow{
+		rowMeta: rowMeta{&meta},
+		Name:    meta.Name,
+		Created: meta.CreationTimestamp.Time,
+		Age:     formatAge(meta.CreationTimestamp.Time),
+	}
+}
+
+// newRevisionRow returns false for ReplicaSets the deployment controller
+// has not numbered.
+func newRevisionRow(rs appsv1.ReplicaSet, currentRevision string) (RevisionRow, bool) {
+	revision, err := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+	if err != nil {
+		return RevisionRow{}, false
+	}
+	var images []string
+	for _, c := range rs.Spec.Template.Spec.Containers {
+		images = append(images, c.Image)
+	}
+
+	return RevisionRow{
+		rowMeta:     rowMeta{&rs.ObjectMeta},
+		Revision:    revision,
+		Current:     rs.Annotations[revisionAnnotation] == currentRevision,
+		ReplicaSet:  rs.Name,
+		Images:      images,
+		ChangeCause: rs.Annotations[changeCauseAnnotation],
+		Created:     rs.CreationTimestamp.Time,
+		Age:         formatAge(rs.CreationTimestamp.Time),
+	}, true
+}
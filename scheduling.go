@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// schedulingTracker measures, for -scheduling-latency, how long pods
+// created during the watch took to be scheduled and then to start
+// running. It outlives the per-tick printer.
+type schedulingTracker struct {
+	started   time.Time
+	done      map[string]bool // pods already reported
+	scheduled []time.Duration // created → scheduled
+	running   []time.Duration // scheduled → running
+}
+
+func newSchedulingTracker() *schedulingTracker {
+	return &schedulingTracker{started: time.Now(), done: map[string]bool{}}
+}
+
+// observe prints one line for each pod that has started running since the
+// previous tick. Times come from the pod's PodScheduled condition and its
+// containers' start times, so they do not depend on the watch interval.
+// cluster keeps the pods of a -resource pods@CLUSTER section apart.
+func (t *schedulingTracker) observe(w io.Writer, cluster string, pods []corev1.Pod) {
+	for _, pod := range pods {
+		key := atCluster(pod.Namespace+"/"+pod.Name, cluster)
+		// older pods were scheduled before anyone was watching
+		if t.done[key] || pod.CreationTimestamp.Time.Before(t.started.Truncate(time.Second)) {
+			continue
+		}
+		scheduledAt, ok := podScheduledTime(pod)
+		if !ok {
+			continue
+		}
+		runningAt, ok := podRunningTime(pod)
+		if !ok {
+			continue
+		}
+		t.done[key] = true
+
+		toSchedule := scheduledAt.Sub(pod.CreationTimestamp.Time)
+		toRun := runningAt.Sub(scheduledAt)
+		t.scheduled = append(t.scheduled, toSchedule)
+		t.running = append(t.running, toRun)
+		fmt.Fprintf(w, "SCHEDULED pod %s on %s: scheduled after %s, running %s later\n", key, pod.Spec.NodeName, toSchedule, toRun)
+	}
+}
+
+// printSummary prints the session's latency percentiles.
+func (t *schedulingTracker) printSummary(w io.Writer) {
+	if len(t.scheduled) == 0 {
+		fmt.Fprintln(w, "\nNo pod created during the watch started running")
+		return
+	}
+	fmt.Fprintf(w, "\nScheduling latency over %d pods created during the watch:\n", len(t.scheduled))
+	fmt.Fprintf(w, "  created → scheduled  p50 %s  p90 %s\n", percentile(t.scheduled, 50), percentile(t.scheduled, 90))
+	fmt.Fprintf(w, "  scheduled → running  p50 %s  p90 %s\n", percentile(t.running, 50), percentile(t.running, 90))
+}
+
+func podScheduledTime(pod corev1.Pod) (time.Time, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// podRunningTime is when the pod's first container started.
+func podRunningTime(pod corev1.Pod) (time.Time, bool) {
+	var first time.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if running := status.State.Running; running != nil && (first.IsZero() || running.StartedAt.Time.Before(first)) {
+			first = running.StartedAt.Time
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// percentile returns the nearest-rank percentile of durations.
+func percentile(durations []time.Duration, p int) time.Duration {
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
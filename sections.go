@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// errorOutput is where handleError prints; a section rendered
+// concurrently points it at its own buffer while it holds the lock.
+var errorOutput io.Writer = os.Stdout
+
+// sectionRun is one resource type of a multi-type tick rendered in its
+// own goroutine. Sections take turns holding mu while they render, and
+// fetch lets go of it for the API call, so the List calls overlap but the
+// printers and trackers they share are never used concurrently.
+type sectionRun struct {
+	mu     *sync.Mutex
+	out    bytes.Buffer // the section's output to p.w
+	stdout bytes.Buffer // its errors, shown on stdout
+	errs   []error      // what handleError reported for it
+	mark   int
+}
+
+func (s *sectionRun) acquire() {
+	s.mu.Lock()
+	s.mark = len(reportedErrors)
+	errorOutput = &s.stdout
+}
+
+func (s *sectionRun) release() {
+	s.errs = append(s.errs, reportedErrors[s.mark:]...)
+	errorOutput = os.Stdout
+	s.mu.Unlock()
+}
+
+// renderSections renders every section concurrently with its own copy of
+// p and src, then writes their output in section order once all are done.
+// It returns the errors each section reported.
+func renderSections(p *printer, src *source, sections []string, render func(p *printer, src *source, resource string)) [][]error {
+	var mu sync.Mutex
+	runs := make([]*sectionRun, len(sections))
+	var g errgroup.Group
+	for i, resource := range sections {
+		resource, run := resource, &sectionRun{mu: &mu}
+		runs[i] = run
+		g.Go(func() error {
+			run.acquire()
+			defer run.release()
+			sp, ssrc := *p, *src
+			sp.w, ssrc.section = &run.out, run
+			render(&sp, &ssrc, resource)
+			return nil
+		})
+	}
+	g.Wait()
+
+	errs := make([][]error, len(runs))
+	for i, run := range runs {
+		os.Stdout.Write(run.stdout.Bytes())
+		p.w.Write(run.out.Bytes())
+		errs[i] = run.errs
+	}
+	return errs
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// sidecarInjector is a mutating webhook known to add containers to pods,
+// with the names of the containers it adds.
+type sidecarInjector struct {
+	name       string
+	containers []string
+}
+
+var sidecarInjectors = []sidecarInjector{
+	{"istio", []string{"istio-proxy", "istio-init", "istio-validation"}},
+	{"linkerd", []string{"linkerd-proxy", "linkerd-init", "linkerd-network-validator"}},
+	{"consul", []string{"consul-dataplane", "envoy-sidecar", "consul-connect-inject-init", "consul-connect-lifecycle-sidecar"}},
+	{"kuma", []string{"kuma-sidecar", "kuma-init", "kuma-validation"}},
+	{"vault", []string{"vault-agent", "vault-agent-init"}},
+	{"dapr", []string{"daprd"}},
+}
+
+// injectedContainers maps the pod's containers that a mutating webhook
+// most likely injected to the injector's name. A container counts when
+// it has a known sidecar name, or when the pod carries Istio's injection
+// status annotation, which lists the containers it added.
+func injectedContainers(pod corev1.Pod) map[string]string {
+	injected := map[string]string{}
+	for _, injector := range sidecarInjectors {
+		for _, name := range injector.containers {
+			injected[name] = injector.name
+		}
+	}
+
+	// {"initContainers":["istio-init"],"containers":["istio-proxy"],...}
+	if status, ok := pod.Annotations["sidecar.istio.io/status"]; ok {
+		var added struct {
+			InitContainers []string `json:"initContainers"`
+			Containers     []string `json:"containers"`
+		}
+		if json.Unmarshal([]byte(status), &added) == nil {
+			for _, name := range append(added.InitContainers, added.Containers...) {
+				injected[name] = "istio"
+			}
+		}
+	}
+
+	found := map[string]string{}
+	for _, list := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, c := range list {
+			if injector, ok := injected[c.Name]; ok {
+				found[c.Name] = injector
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// rowSorter orders rows for -sort-by and -pin. With -sticky-sort, rows
+// keep the position they had when first seen, so they do not jump around
+// while watching; the rows whose sort value changed since the previous
+// tick are marked instead. It outlives the per-tick printer.
+type rowSorter struct {
+	field    string   // -sort-by; "" keeps the API order
+	reverse  bool     // -reverse
+	pinned   []string // -pin names, shown first in this order
+	sticky   bool
+	position map[string]map[string]int    // per kind
+	values   map[string]map[string]string // per kind: the last sort value
+	next     int
+}
+
+func newRowSorter(field string, reverse bool, pinned []string, sticky bool) *rowSorter {
+	return &rowSorter{
+		field:    strings.ToLower(field),
+		reverse:  reverse,
+		pinned:   pinned,
+		sticky:   sticky,
+		position: map[string]map[string]int{},
+		values:   map[string]map[string]string{},
+	}
+}
+
+// sortRows returns the rows in display order and, with -sticky-sort,
+// which of them changed their sort value since the previous tick.
+func sortRows[T any](s *rowSorter, kind string, columns []column[T], rows []T) ([]T, []bool, error) {
+	sortBy := -1
+	if s.field != "" {
+		for i, col := range columns {
+			if fieldName(col) == s.field {
+				sortBy = i
+			}
+		}
+		if sortBy < 0 {
+			valid := make([]string, len(columns))
+			for i, col := range columns {
+				valid[i] = fieldName(col)
+			}
+			return nil, nil, fmt.Errorf("unknown -sort-by field %q; valid fields are: %s", s.field, strings.Join(valid, ", "))
+		}
+	}
+
+	type entry struct {
+		row     T
+		key     string
+		name    string
+		value   string
+		created time.Time
+	}
+	entries := make([]entry, len(rows))
+	keys := rowKeys{}
+	for i, row := range rows {
+		cells := make([]string, len(columns))
+		for j, col := range columns {
+			cells[j] = col.value(row)
+		}
+		key := keys.next(rowKey(row, cells))
+		name := key[strings.LastIndex(key, "/")+1:]
+		if len(cells) > 0 && cells[0] != "" {
+			name = cells[0]
+		}
+		entry := entry{row: row, key: key, name: name}
+		if sortBy >= 0 {
+			entry.value = cells[sortBy]
+		}
+		if m, ok := any(row).(interface{ objectMeta() metav1.Object }); ok && m.objectMeta() != nil {
+			entry.created = m.objectMeta().GetCreationTimestamp().Time
+		}
+		entries[i] = entry
+	}
+
+	if sortBy >= 0 {
+		less := func(a, b entry) bool { return cellLess(a.value, b.value) }
+		if s.field == "age" && len(entries) > 0 && !entries[0].created.IsZero() {
+			// "2d" and "30m" do not compare as text; the youngest, created
+			// last, has the smallest age
+			less = func(a, b entry) bool { return a.created.After(b.created) }
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			if s.reverse {
+				return less(entries[j], entries[i])
+			}
+			return less(entries[i], entries[j])
+		})
+	}
+
+	var changed map[string]bool
+	if s.sticky {
+		if s.position[kind] == nil {
+			s.position[kind] = map[string]int{}
+			s.values[kind] = map[string]string{}
+		}
+		position, values := s.position[kind], s.values[kind]
+		changed = map[string]bool{}
+		current := map[string]bool{}
+		for _, e := range entries {
+			current[e.key] = true
+			if _, ok := position[e.key]; !ok {
+				// new rows go below the ones already on screen
+				position[e.key] = s.next
+				s.next++
+			} else if values[e.key] != e.value {
+				changed[e.key] = true
+			}
+			values[e.key] = e.value
+		}
+		for key := range position {
+			if !current[key] {
+				delete(position, key)
+				delete(values, key)
+			}
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return position[entries[i].key] < position[entries[j].key] })
+	}
+
+	if len(s.pinned) > 0 {
+		rank := func(name string) int {
+			for i, pinned := range s.pinned {
+				if pinned == name {
+					return i
+				}
+			}
+			return len(s.pinned)
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return rank(entries[i].name) < rank(entries[j].name) })
+	}
+
+	sorted := make([]T, len(entries))
+	var marked []bool
+	if s.sticky {
+		marked = make([]bool, len(entries))
+	}
+	for i, e := range entries {
+		sorted[i] = e.row
+		if s.sticky {
+			marked[i] = changed[e.key]
+		}
+	}
+	return sorted, marked, nil
+}
+
+// groupRows gathers rows with the same value of field together, groups in
+// order of that value and rows in their current order within each, and
+// returns each row's group value. marked, if set, is reordered with them.
+func groupRows[T any](field string, columns []column[T], rows []T, marked []bool) ([]T, []bool, []string, error) {
+	groupBy := -1
+	for i, col := range columns {
+		if fieldName(col) == field {
+			groupBy = i
+		}
+	}
+	if groupBy < 0 {
+		valid := make([]string, len(columns))
+		for i, col := range columns {
+			valid[i] = fieldName(col)
+		}
+		return nil, nil, nil, fmt.Errorf("unknown -group-by field %q; valid fields are: %s", field, strings.Join(valid, ", "))
+	}
+
+	order := make([]int, len(rows))
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		order[i] = i
+		values[i] = columns[groupBy].value(row)
+	}
+	sort.SliceStable(order, func(i, j int) bool { return cellLess(values[order[i]], values[order[j]]) })
+
+	grouped := make([]T, len(rows))
+	groups := make([]string, len(rows))
+	var groupedMarks []bool
+	if marked != nil {
+		groupedMarks = make([]bool, len(rows))
+	}
+	for i, from := range order {
+		grouped[i] = rows[from]
+		groups[i] = values[from]
+		if marked != nil {
+			groupedMarks[i] = marked[from]
+		}
+	}
+	return grouped, groupedMarks, groups, nil
+}
+
+// cellLess compares two cells as numbers when both are, and as text
+// otherwise.
+func cellLess(a, b string) bool {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return a < b
+}
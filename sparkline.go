@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sparkBlocks are the sparkline's levels, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// countHistory keeps, for -sparkline, each resource type's count over the
+// last ticks of a watch in a ring buffer per type, so a trend such as pods
+// climbing during a scale-up shows next to the total. It outlives the
+// per-tick printer.
+type countHistory struct {
+	size  int
+	rings map[string]*countRing // per kind, and cluster for TYPE@CLUSTER
+}
+
+// countRing holds the last len(samples) counts; next is where the next one
+// goes, and the oldest once the ring is full.
+type countRing struct {
+	samples []int
+	next    int
+	full    bool
+}
+
+func newCountHistory(size int) *countHistory {
+	return &countHistory{size: size, rings: map[string]*countRing{}}
+}
+
+// observe records this tick's count for key and returns the sparkline of
+// the counts so far, oldest first, with their range, e.g. "▁▃▆█ (8-12
+// over 4 ticks)".
+func (h *countHistory) observe(key string, count int) string {
+	ring := h.rings[key]
+	if ring == nil {
+		ring = &countRing{samples: make([]int, h.size)}
+		h.rings[key] = ring
+	}
+	ring.samples[ring.next] = count
+	ring.next = (ring.next + 1) % len(ring.samples)
+	if ring.next == 0 {
+		ring.full = true
+	}
+	counts := ring.ordered()
+	low, high := countRange(counts)
+	return fmt.Sprintf("%s (%d-%d over %d ticks)", sparkline(counts), low, high, len(counts))
+}
+
+func (r *countRing) ordered() []int {
+	if !r.full {
+		return r.samples[:r.next]
+	}
+	return append(append([]int{}, r.samples[r.next:]...), r.samples[:r.next]...)
+}
+
+// sparkline draws counts scaled between their minimum and maximum; a flat
+// history is drawn on the lowest level.
+func sparkline(counts []int) string {
+	low, high := countRange(counts)
+	var line strings.Builder
+	for _, count := range counts {
+		level := 0
+		if high > low {
+			level = (count - low) * (len(sparkBlocks) - 1) / (high - low)
+		}
+		line.WriteRune(sparkBlocks[level])
+	}
+	return line.String()
+}
+
+func countRange(counts []int) (int, int) {
+	if len(counts) == 0 {
+		return 0, 0
+	}
+	low, high := counts[0], counts[0]
+	for _, count := range counts {
+		low, high = min(low, count), max(high, count)
+	}
+	return low, high
+}
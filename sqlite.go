@@ -0,0 +1,131 @@
+package main
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema is created on first use. Status, restarts and ready are
+// NULL for kinds without those columns; cluster is empty outside
+// TYPE@CLUSTER sections.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS samples (
+	time      TIMESTAMP NOT NULL,
+	kind      TEXT NOT NULL,
+	cluster   TEXT NOT NULL DEFAULT '',
+	namespace TEXT NOT NULL,
+	name      TEXT NOT NULL,
+	status    TEXT,
+	restarts  INTEGER,
+	ready     TEXT
+);
+CREATE INDEX IF NOT EXISTS samples_object ON samples (kind, namespace, name, time);
+`
+
+// sample is one object's state at one tick, a row of the samples table.
+type sample struct {
+	time      time.Time
+	kind      string
+	cluster   string
+	namespace string
+	name      string
+	status    sql.NullString
+	restarts  sql.NullInt64
+	ready     sql.NullString
+}
+
+// sampleStore records every listed object's state each tick to the
+// -sqlite database for querying trends afterwards. Samples are buffered
+// and written by flush in one transaction per tick.
+type sampleStore struct {
+	db      *sql.DB
+	pending []sample
+}
+
+func openSampleStore(path string) (*sampleStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	// databases created before samples had a cluster column
+	if _, err := db.Exec("ALTER TABLE samples ADD COLUMN cluster TEXT NOT NULL DEFAULT ''"); err != nil && !strings.Contains(err.Error(), "duplicate column") {
+		db.Close()
+		return nil, err
+	}
+	return &sampleStore{db: db}, nil
+}
+
+// recordSamples buffers a sample for each of this tick's rows, reading
+// STATUS, RESTARTS and READY from the columns that have them.
+func recordSamples[T any](s *sampleStore, kind, cluster string, columns []column[T], rows []T) {
+	index := map[string]int{}
+	for i, col := range columns {
+		index[col.header] = i
+	}
+	statuses, _ := rowStatuses(columns, rows)
+
+	now := time.Now().UTC()
+	for i, row := range rows {
+		cells := make([]string, len(columns))
+		for j, col := range columns {
+			cells[j] = col.value(row)
+		}
+		sample := sample{time: now, kind: strings.TrimSuffix(kind, "s"), cluster: cluster, name: rowKey(row, cells)}
+		if namespace, name, ok := strings.Cut(sample.name, "/"); ok {
+			sample.namespace, sample.name = namespace, name
+		}
+		if statuses != nil {
+			sample.status = sql.NullString{String: statuses[i].status, Valid: true}
+		}
+		if j, ok := index["RESTARTS"]; ok {
+			if restarts, err := strconv.Atoi(cells[j]); err == nil {
+				sample.restarts = sql.NullInt64{Int64: int64(restarts), Valid: true}
+			}
+		}
+		if j, ok := index["READY"]; ok {
+			sample.ready = sql.NullString{String: cells[j], Valid: true}
+		}
+		s.pending = append(s.pending, sample)
+	}
+}
+
+// flush writes the tick's samples in one transaction.
+func (s *sampleStore) flush() error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	insert, err := tx.Prepare("INSERT INTO samples (time, kind, cluster, namespace, name, status, restarts, ready) VALUES (?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer insert.Close()
+	for _, sample := range s.pending {
+		if _, err := insert.Exec(sample.time, sample.kind, sample.cluster, sample.namespace, sample.name, sample.status, sample.restarts, sample.ready); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	s.pending = nil
+	return tx.Commit()
+}
+
+func (s *sampleStore) close() error {
+	err := s.flush()
+	if closeErr := s.db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
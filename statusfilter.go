@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podStatuses are the statuses -status selects pods by: what getPodStatus
+// shows, and the container waiting reasons that keep a pod from running.
+func podStatuses() []string {
+	statuses := []string{
+		string(corev1.PodPending), string(corev1.PodRunning), string(corev1.PodSucceeded),
+		string(corev1.PodFailed), string(corev1.PodUnknown),
+		"Terminating", "Evicted", "Starting", "NotReady",
+	}
+	for reason := range badWaitingReasons {
+		statuses = append(statuses, reason)
+	}
+	sort.Strings(statuses)
+	return statuses
+}
+
+// parseStatusFilter parses -status, a comma-separated list of statuses
+// matched regardless of case.
+func parseStatusFilter(value string) (map[string]bool, error) {
+	valid := podStatuses()
+	selected := map[string]bool{}
+	for _, status := range splitList(value) {
+		found := false
+		for _, name := range valid {
+			if strings.EqualFold(status, name) {
+				selected[name] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown pod status %q; valid statuses are: %s", status, strings.Join(valid, ", "))
+		}
+	}
+	return selected, nil
+}
+
+// podHasStatus reports whether the pod is in any of the selected statuses:
+// its STATUS, "Terminating (12m)" counting as Terminating, or the waiting
+// reason of one of its containers, so a Running pod with a container in
+// CrashLoopBackOff is selected by either.
+func podHasStatus(pod corev1.Pod, selected map[string]bool) bool {
+	status, _, _ := strings.Cut(getPodStatus(pod), " ")
+	if selected[status] {
+		return true
+	}
+	for _, list := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, container := range list {
+			if container.State.Waiting != nil && selected[container.State.Waiting.Reason] {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var volumeAttachmentColumns = []column[VolumeAttachmentRow]{
+	{"NAME", 50, func(r VolumeAttachmentRow) string { return r.Name }, nil},
+	{"ATTACHER", 30, func(r VolumeAttachmentRow) string { return r.Attacher }, nil},
+	{"PV", 45, func(r VolumeAttachmentRow) string { return r.PV }, nil},
+	{"NODE", 30, func(r VolumeAttachmentRow) string { return r.Node }, nil},
+	{"ATTACHED", 10, func(r VolumeAttachmentRow) string { return strconv.FormatBool(r.Attached) }, nil},
+	{"AGE", 10, func(r VolumeAttachmentRow) string { return r.Age }, ignored[VolumeAttachmentRow]},
+}
+
+func listVolumeAttachments(ctx context.Context, p *printer, src *source, opts metav1.ListOptions) {
+	attachments, err := fetch(src, &storagev1.VolumeAttachmentList{}, "", opts, func() (*storagev1.VolumeAttachmentList, error) {
+		return src.clientset.StorageV1().VolumeAttachments().List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []VolumeAttachmentRow
+	for _, va := range attachments.Items {
+		rows = append(rows, newVolumeAttachmentRow(va))
+	}
+	printRows(p, "volumeattachments", attachments, volumeAttachmentColumns, rows)
+}
+
+var csiDriverColumns = []column[CSIDriverRow]{
+	{"NAME", 40, func(r CSIDriverRow) string { return r.Name }, nil},
+	{"ATTACHREQUIRED", 16, func(r CSIDriverRow) string { return strconv.FormatBool(r.AttachRequired) }, nil},
+	{"PODINFOONMOUNT", 16, func(r CSIDriverRow) string { return strconv.FormatBool(r.PodInfoOnMount) }, nil},
+	{"STORAGECAPACITY", 17, func(r CSIDriverRow) string { return strconv.FormatBool(r.StorageCapacity) }, nil},
+	{"MODES", 30, func(r CSIDriverRow) string { return orNone(strings.Join(r.Modes, ",")) }, nil},
+	{"AGE", 10, func(r CSIDriverRow) string { return r.Age }, ignored[CSIDriverRow]},
+}
+
+// listCSIDrivers lists the CSI drivers registered with the cluster.
+func listCSIDrivers(ctx context.Context, p *printer, src *source, opts metav1.ListOptions) {
+	drivers, err := fetch(src, &storagev1.CSIDriverList{}, "", opts, func() (*storagev1.CSIDriverList, error) {
+		return src.clientset.StorageV1().CSIDrivers().List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []CSIDriverRow
+	for _, driver := range drivers.Items {
+		rows = append(rows, newCSIDriverRow(driver))
+	}
+	printRows(p, "csidrivers", drivers, csiDriverColumns, rows)
+}
+
+var csiNodeColumns = []column[CSINodeRow]{
+	{"NODE", 30, func(r CSINodeRow) string { return r.Node }, nil},
+	{"DRIVER", 40, func(r CSINodeRow) string { return orNone(r.Driver) }, nil},
+	{"NODEID", 40, func(r CSINodeRow) string { return orNone(r.NodeID) }, nil},
+	{"MAX-VOLUMES", 12, func(r CSINodeRow) string {
+		if r.MaxVolumes == nil {
+			return "-"
+		}
+		return strconv.Itoa(int(*r.MaxVolumes))
+	}, nil},
+	{"AGE", 10, func(r CSINodeRow) string { return r.Age }, ignored[CSINodeRow]},
+}
+
+// listCSINodes lists, per node, the CSI drivers its kubelet has
+// registered and the node's ID in each. A volume of a driver missing here
+// cannot attach to the node. Nodes without any driver get one row with
+// none.
+func listCSINodes(ctx context.Context, p *printer, src *source, opts metav1.ListOptions) {
+	csiNodes, err := fetch(src, &storagev1.CSINodeList{}, "", opts, func() (*storagev1.CSINodeList, error) {
+		return src.clientset.StorageV1().CSINodes().List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []CSINodeRow
+	for _, csiNode := range csiNodes.Items {
+		rows = append(rows, newCSINodeRows(csiNode)...)
+	}
+	printRows(p, "csinodes", csiNodes, csiNodeColumns, rows)
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// healthSchemaVersion is bumped whenever the HealthSummary JSON changes
+// incompatibly, so alerting that polls -summary -o json can pin it.
+const healthSchemaVersion = 1
+
+// HealthSummary is the -summary document.
+type HealthSummary struct {
+	SchemaVersion int                    `json:"schemaVersion"`
+	GeneratedAt   time.Time              `json:"generatedAt"`
+	Namespace     string                 `json:"namespace"`
+	Healthy       bool                   `json:"healthy"`
+	Resources     map[string]HealthCount `json:"resources"`
+	Unhealthy     []UnhealthyObject      `json:"unhealthy"`
+}
+
+// HealthCount is how many objects of one type were checked and how many
+// of them are unhealthy.
+type HealthCount struct {
+	Total     int `json:"total"`
+	Unhealthy int `json:"unhealthy"`
+}
+
+// UnhealthyObject is one object that failed its health check.
+type UnhealthyObject struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+}
+
+// summaryResources are the types -summary checks, and -only-problems
+// without -resource.
+var summaryResources = []string{"pods", "deployments", "services", "nodes"}
+
+// printSummary checks the namespace's pods, deployments and services and
+// the cluster's nodes, and prints one health verdict for all of them.
+func printSummary(ctx context.Context, p *printer, src *source, namespace string, opts metav1.ListOptions) {
+	summary, err := buildSummary(ctx, src, namespace, opts)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	switch p.format {
+	case "json":
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			handleError(err)
+			return
+		}
+		fmt.Fprintln(p.w, string(data))
+	case "table":
+		verdict := "HEALTHY"
+		if !summary.Healthy {
+			verdict = "UNHEALTHY"
+		}
+		fmt.Fprintf(p.w, "\nNamespace %s: %s\n\n", namespace, verdict)
+		kinds := make([]string, 0, len(summary.Resources))
+		for kind := range summary.Resources {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			count := summary.Resources[kind]
+			fmt.Fprintf(p.w, "%-15s %d total, %d unhealthy\n", kind, count.Total, count.Unhealthy)
+		}
+		if len(summary.Unhealthy) > 0 {
+			fmt.Fprintln(p.w, "\nUnhealthy:")
+			for _, object := range summary.Unhealthy {
+				fmt.Fprintf(p.w, "  %s %s: %s\n", object.Kind, strings.TrimPrefix(object.Namespace+"/"+object.Name, "/"), object.Reason)
+			}
+		}
+	default:
+		fmt.Printf("Error: -summary only supports -output table or json\n")
+		os.Exit(1)
+	}
+}
+
+func buildSummary(ctx context.Context, src *source, namespace string, opts metav1.ListOptions) (*HealthSummary, error) {
+	summary := &HealthSummary{
+		SchemaVersion: healthSchemaVersion,
+		GeneratedAt:   time.Now().UTC(),
+		Namespace:     namespace,
+		Resources:     map[string]HealthCount{},
+		Unhealthy:     []UnhealthyObject{},
+	}
+	for _, resource := range summaryResources {
+		total, unhealthy, _, err := checkHealth(ctx, src, resource, namespace, opts)
+		if err != nil {
+			return nil, err
+		}
+		summary.Resources[resource] = HealthCount{Total: total, Unhealthy: len(unhealthy)}
+		summary.Unhealthy = append(summary.Unhealthy, unhealthy...)
+	}
+	quotaWarnings, err := podQuotaWarnings(ctx, src, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range quotaWarnings {
+		summary.Unhealthy = append(summary.Unhealthy, UnhealthyObject{"resourcequota", warning.namespace, warning.quota,
+			fmt.Sprintf("pods quota %d/%d used", warning.used, warning.hard)})
+	}
+
+	summary.Healthy = len(summary.Unhealthy) == 0
+	return summary, nil
+}
+
+// checkHealth counts the objects of one resource type and returns the
+// unhealthy ones. It returns false for types it does not know how to
+// list; configmaps and secrets are counted but never unhealthy.
+func checkHealth(ctx context.Context, src *source, resource, namespace string, opts metav1.ListOptions) (int, []UnhealthyObject, bool, error) {
+	var unhealthy []UnhealthyObject
+	switch resource {
+	case "pods":
+		pods, err := fetch(src, &corev1.PodList{}, namespace, opts, func() (*corev1.PodList, error) {
+			return src.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			return 0, nil, true, err
+		}
+		for _, pod := range pods.Items {
+			if problem := getPodProblem(pod); problem != "" {
+				unhealthy = append(unhealthy, UnhealthyObject{"pod", pod.Namespace, pod.Name, problem})
+			}
+		}
+		return len(pods.Items), unhealthy, true, nil
+
+	case "deployments":
+		deployments, err := fetch(src, &appsv1.DeploymentList{}, namespace, opts, func() (*appsv1.DeploymentList, error) {
+			return src.clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			return 0, nil, true, err
+		}
+		for _, deployment := range deployments.Items {
+			if desired := getDesiredReplicas(deployment); deployment.Status.AvailableReplicas < desired {
+				reason := fmt.Sprintf("%d/%d available", deployment.Status.AvailableReplicas, desired)
+				if problem := deploymentConditionProblem(deployment); problem != "" {
+					reason += ", " + problem
+				}
+				unhealthy = append(unhealthy, UnhealthyObject{"deployment", deployment.Namespace, deployment.Name, reason})
+			}
+		}
+		return len(deployments.Items), unhealthy, true, nil
+
+	case "replicationcontrollers":
+		controllers, err := fetch(src, &corev1.ReplicationControllerList{}, namespace, opts, func() (*corev1.ReplicationControllerList, error) {
+			return src.clientset.CoreV1().ReplicationControllers(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			return 0, nil, true, err
+		}
+		for _, rc := range controllers.Items {
+			if row := newReplicationControllerRow(rc); row.Ready < row.Desired {
+				unhealthy = append(unhealthy, UnhealthyObject{"replicationcontroller", rc.Namespace, rc.Name,
+					fmt.Sprintf("%d/%d ready", row.Ready, row.Desired)})
+			}
+		}
+		return len(controllers.Items), unhealthy, true, nil
+
+	case "services":
+		notServing, total, err := servicesWithoutEndpoints(ctx, src, namespace, opts)
+		if err != nil {
+			return 0, nil, true, err
+		}
+		for _, name := range notServing {
+			unhealthy = append(unhealthy, UnhealthyObject{"service", namespace, name, "no ready endpoints"})
+		}
+		return total, unhealthy, true, nil
+
+	case "nodes":
+		nodes, err := fetch(src, &corev1.NodeList{}, "", metav1.ListOptions{}, func() (*corev1.NodeList, error) {
+			return src.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		})
+		if err != nil {
+			return 0, nil, true, err
+		}
+		for _, node := range nodes.Items {
+			if problems := getNodeProblems(node.Status.Conditions); getNodeStatus(node) != "Ready" || len(problems) > 0 {
+				unhealthy = append(unhealthy, UnhealthyObject{"node", "", node.Name, strings.Join(append([]string{getNodeStatus(node)}, problems...), ", ")})
+			}
+		}
+		return len(nodes.Items), unhealthy, true, nil
+
+	case "leases":
+		leases, err := fetch(src, &coordinationv1.LeaseList{}, namespace, opts, func() (*coordinationv1.LeaseList, error) {
+			return src.clientset.CoordinationV1().Leases(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			return 0, nil, true, err
+		}
+		for _, lease := range leases.Items {
+			if leaseStale(lease) {
+				unhealthy = append(unhealthy, UnhealthyObject{"lease", lease.Namespace, lease.Name,
+					"not renewed for " + formatAge(lease.Spec.RenewTime.Time)})
+			}
+		}
+		return len(leases.Items), unhealthy, true, nil
+
+	case "configmaps":
+		configMaps, err := fetch(src, &corev1.ConfigMapList{}, namespace, opts, func() (*corev1.ConfigMapList, error) {
+			return src.clientset.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			return 0, nil, true, err
+		}
+		return len(configMaps.Items), nil, true, nil
+
+	case "secrets":
+		secrets, err := fetch(src, &corev1.SecretList{}, namespace, opts, func() (*corev1.SecretList, error) {
+			return src.clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			return 0, nil, true, err
+		}
+		return len(secrets.Items), nil, true, nil
+	}
+	return 0, nil, false, nil
+}
+
+// printCollapsed prints one resource type's section of a multi-type watch
+// as a single line: its count and which objects are unhealthy.
+func printCollapsed(ctx context.Context, p *printer, src *source, resource, namespace string, opts metav1.ListOptions) {
+	total, unhealthy, ok, err := checkHealth(ctx, src, resource, namespace, opts)
+	if err != nil {
+		handleError(err)
+		return
+	}
+	label := atCluster(resource, p.cluster)
+	if !ok {
+		fmt.Fprintf(p.w, "\n▸ %s (no summary, use -expand %s)\n", label, label)
+		return
+	}
+	if p.metrics != nil {
+		p.metrics.count(resource, p.cluster, total)
+	}
+	trend := ""
+	if p.sparklines != nil {
+		trend = "  " + p.sparklines.observe(label, total)
+	}
+	if len(unhealthy) == 0 {
+		fmt.Fprintf(p.w, "\n▸ %s: %d%s\n", label, total, trend)
+		return
+	}
+	names := make([]string, len(unhealthy))
+	for i, object := range unhealthy {
+		names[i] = fmt.Sprintf("%s(%s)", object.Name, object.Reason)
+	}
+	fmt.Fprintf(p.w, "\n▸ %s: %d%s, %d unhealthy: %s\n", label, total, trend, len(unhealthy), strings.Join(names, ", "))
+}
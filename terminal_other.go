@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package main
+
+import "golang.org/x/term"
+
+// makeCbreak falls back to raw mode where the terminal has no cbreak
+// mode. Ctrl+C then arrives as a key, which the keyboard reader turns
+// into a cancel.
+func makeCbreak(fd int) (func() error, error) {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return func() error { return term.Restore(fd, state) }, nil
+}
@@ -0,0 +1,25 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package main
+
+import "golang.org/x/sys/unix"
+
+// makeCbreak turns off line buffering and echo on the terminal, so keys
+// arrive one at a time. Unlike raw mode it keeps signals, so Ctrl+C still
+// interrupts while nothing reads the keyboard, and output processing, so
+// "\n" still starts a new line. The returned function restores the
+// previous mode.
+func makeCbreak(fd int) (func() error, error) {
+	previous, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+	cbreak := *previous
+	cbreak.Lflag &^= unix.ICANON | unix.ECHO
+	cbreak.Cc[unix.VMIN] = 1
+	cbreak.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &cbreak); err != nil {
+		return nil, err
+	}
+	return func() error { return unix.IoctlSetTermios(fd, ioctlSetTermios, previous) }, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// outputThrottle caps the -events and -watch-on-change-only update lines
+// at -max-output-rate per second, so a mass restart cannot flood the
+// terminal. Lines over the cap are dropped and counted for a
+// "(+N more updates)" line. It outlives the per-tick printer.
+type outputThrottle struct {
+	mu      sync.Mutex
+	max     int
+	start   time.Time // of the current second
+	lines   int
+	dropped int
+}
+
+func newOutputThrottle(max int) *outputThrottle {
+	return &outputThrottle{max: max}
+}
+
+// allow counts a line and reports whether it fits this second's budget.
+func (t *outputThrottle) allow() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if now := time.Now(); now.Sub(t.start) >= time.Second {
+		t.start, t.lines = now, 0
+	}
+	t.lines++
+	if t.lines > t.max {
+		t.dropped++
+		return false
+	}
+	return true
+}
+
+func (t *outputThrottle) takeDropped() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	dropped := t.dropped
+	t.dropped = 0
+	return dropped
+}
+
+// writer returns a writer passing w only the whole lines that fit the
+// budget.
+func (t *outputThrottle) writer(w io.Writer) *throttledWriter {
+	return &throttledWriter{throttle: t, w: w}
+}
+
+type throttledWriter struct {
+	mu       sync.Mutex
+	throttle *outputThrottle
+	w        io.Writer
+	partial  []byte // the unfinished last line
+}
+
+func (tw *throttledWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.partial = append(tw.partial, b...)
+	for {
+		end := bytes.IndexByte(tw.partial, '\n')
+		if end < 0 {
+			return len(b), nil
+		}
+		line := tw.partial[:end+1]
+		tw.partial = tw.partial[end+1:]
+		if tw.throttle.allow() {
+			if _, err := tw.w.Write(line); err != nil {
+				return len(b), err
+			}
+		}
+	}
+}
+
+// flush prints how many lines were dropped since the last flush, if any.
+func (tw *throttledWriter) flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if dropped := tw.throttle.takeDropped(); dropped > 0 {
+		fmt.Fprintf(tw.w, "(+%d more updates)\n", dropped)
+	}
+}
+
+// run flushes once a second until ctx is done, for a stream of lines
+// with no natural end to summarize at.
+func (tw *throttledWriter) run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			tw.flush()
+			return
+		case <-ticker.C:
+			tw.flush()
+		}
+	}
+}
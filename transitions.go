@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// transitionLog remembers the last STATUS of every object across watch
+// ticks for -transitions, so each change can be reported with how long the
+// previous state was held. It outlives the per-tick printer.
+type transitionLog struct {
+	states  map[string]map[string]heldState // per kind, and cluster for TYPE@CLUSTER
+	recent  []string
+	history int // past transitions reprinted each tick; 0 prints only new ones
+}
+
+type heldState struct {
+	state string
+	since time.Time
+}
+
+func newTransitionLog(history int) *transitionLog {
+	return &transitionLog{states: map[string]map[string]heldState{}, history: history}
+}
+
+// recordTransitions compares each row's STATUS with the previous tick and
+// prints the transitions. Objects seen for the first time are timed from
+// the tick they appeared, so the first held duration is a lower bound.
+// Kinds without a STATUS column are ignored.
+func recordTransitions[T any](w io.Writer, t *transitionLog, kind, cluster string, columns []column[T], rows []T) {
+	statuses, ok := rowStatuses(columns, rows)
+	if !ok {
+		return
+	}
+
+	section := atCluster(kind, cluster)
+	if t.states[section] == nil {
+		t.states[section] = map[string]heldState{}
+	}
+	states := t.states[section]
+
+	now := time.Now()
+	seen := map[string]bool{}
+	var lines []string
+	for _, row := range statuses {
+		seen[row.key] = true
+		previous, ok := states[row.key]
+		if ok && previous.state == row.status {
+			continue
+		}
+		if ok {
+			lines = append(lines, fmt.Sprintf("%s %s %s: %s→%s (held %s for %s)",
+				now.Format("15:04:05"), strings.TrimSuffix(kind, "s"), atCluster(row.key, cluster),
+				previous.state, row.status, previous.state, now.Sub(previous.since).Round(time.Second)))
+		}
+		states[row.key] = heldState{state: row.status, since: now}
+	}
+	for key := range states {
+		if !seen[key] {
+			delete(states, key)
+		}
+	}
+
+	if t.history == 0 {
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+		return
+	}
+
+	t.recent = append(t.recent, lines...)
+	if len(t.recent) > t.history {
+		t.recent = t.recent[len(t.recent)-t.history:]
+	}
+	if len(t.recent) > 0 {
+		fmt.Fprintln(w, "\nRecent transitions:")
+		for _, line := range t.recent {
+			fmt.Fprintln(w, "  "+line)
+		}
+	}
+}
+
+type keyedStatus struct {
+	key    string
+	status string
+}
+
+// rowStatuses returns each row's key and STATUS cell, less any detail in
+// parentheses, or false for kinds without a STATUS column.
+func rowStatuses[T any](columns []column[T], rows []T) ([]keyedStatus, bool) {
+	status := -1
+	for i, col := range columns {
+		if col.header == "STATUS" {
+			status = i
+			break
+		}
+	}
+	if status < 0 {
+		return nil, false
+	}
+
+	statuses := make([]keyedStatus, len(rows))
+	for i, row := range rows {
+		cells := make([]string, len(columns))
+		for j, col := range columns {
+			cells[j] = col.value(row)
+		}
+		// a parenthesized detail, such as how long a pod has been
+		// terminating, is not a change of status
+		current, _, _ := strings.Cut(cells[status], " (")
+		statuses[i] = keyedStatus{rowKey(row, cells), current}
+	}
+	return statuses, true
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// treeNode is one object in the -tree view.
+type treeNode struct {
+	kind     string
+	meta     metav1.Object
+	status   string
+	children []*treeNode
+}
+
+// printTree renders the namespace's workloads as Deployment → ReplicaSet →
+// Pod, StatefulSet → Pod and DaemonSet → Pod, following ownerReferences.
+// Objects whose owner is not one of these, such as bare pods, are roots.
+// -l and -name select roots; their descendants are always shown.
+func printTree(ctx context.Context, p *printer, src *source, namespace, selector, name string) {
+	if p.format != "table" {
+		fmt.Printf("Error: -tree only supports -output table\n")
+		os.Exit(1)
+	}
+	rootSelector, err := labels.Parse(selector)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	all := metav1.ListOptions{}
+	deployments, err := fetch(src, &appsv1.DeploymentList{}, namespace, all, func() (*appsv1.DeploymentList, error) {
+		return src.clientset.AppsV1().Deployments(namespace).List(ctx, all)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	replicaSets, err := fetch(src, &appsv1.ReplicaSetList{}, namespace, all, func() (*appsv1.ReplicaSetList, error) {
+		return src.clientset.AppsV1().ReplicaSets(namespace).List(ctx, all)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	statefulSets, err := fetch(src, &appsv1.StatefulSetList{}, namespace, all, func() (*appsv1.StatefulSetList, error) {
+		return src.clientset.AppsV1().StatefulSets(namespace).List(ctx, all)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	daemonSets, err := fetch(src, &appsv1.DaemonSetList{}, namespace, all, func() (*appsv1.DaemonSetList, error) {
+		return src.clientset.AppsV1().DaemonSets(namespace).List(ctx, all)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+	pods, err := fetch(src, &corev1.PodList{}, namespace, all, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods(namespace).List(ctx, all)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var nodes []*treeNode
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		nodes = append(nodes, &treeNode{"Deployment", d, fmt.Sprintf("ready %d/%d", d.Status.ReadyReplicas, getDesiredReplicas(*d)), nil})
+	}
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		desired := int32(1)
+		if rs.Spec.Replicas != nil {
+			desired = *rs.Spec.Replicas
+		}
+		// old ReplicaSets scaled to zero are only noise in a tree
+		if desired == 0 && rs.Status.Replicas == 0 {
+			continue
+		}
+		nodes = append(nodes, &treeNode{"ReplicaSet", rs, fmt.Sprintf("ready %d/%d", rs.Status.ReadyReplicas, desired), nil})
+	}
+	for i := range statefulSets.Items {
+		ss := &statefulSets.Items[i]
+		desired := int32(1)
+		if ss.Spec.Replicas != nil {
+			desired = *ss.Spec.Replicas
+		}
+		nodes = append(nodes, &treeNode{"StatefulSet", ss, fmt.Sprintf("ready %d/%d", ss.Status.ReadyReplicas, desired), nil})
+	}
+	for i := range daemonSets.Items {
+		ds := &daemonSets.Items[i]
+		nodes = append(nodes, &treeNode{"DaemonSet", ds, fmt.Sprintf("ready %d/%d", ds.Status.NumberReady, ds.Status.DesiredNumberScheduled), nil})
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		nodes = append(nodes, &treeNode{"Pod", pod, fmt.Sprintf("%s %d/%d", pod.Status.Phase, getReadyContainers(pod.Status.ContainerStatuses), len(pod.Spec.Containers)), nil})
+	}
+
+	byUID := map[types.UID]*treeNode{}
+	for _, node := range nodes {
+		byUID[node.meta.GetUID()] = node
+	}
+	var roots []*treeNode
+	for _, node := range nodes {
+		if parent := treeParent(node, byUID); parent != nil {
+			parent.children = append(parent.children, node)
+			continue
+		}
+		if (name == "" || node.meta.GetName() == name) && rootSelector.Matches(labels.Set(node.meta.GetLabels())) {
+			roots = append(roots, node)
+		}
+	}
+
+	fmt.Fprintln(p.w)
+	sortTree(roots)
+	for _, root := range roots {
+		printTreeNode(p.w, root, "", "")
+	}
+	fmt.Fprintf(p.w, "\nTotal workloads: %d\n", len(roots))
+}
+
+// treeParent returns the fetched owner of node, nil if it has none.
+func treeParent(node *treeNode, byUID map[types.UID]*treeNode) *treeNode {
+	for _, owner := range node.meta.GetOwnerReferences() {
+		// an empty UID would match objects from a dump that have none
+		if parent, ok := byUID[owner.UID]; ok && owner.UID != "" && parent != node {
+			return parent
+		}
+	}
+	return nil
+}
+
+func sortTree(nodes []*treeNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].kind != nodes[j].kind {
+			return nodes[i].kind < nodes[j].kind
+		}
+		return nodes[i].meta.GetName() < nodes[j].meta.GetName()
+	})
+	for _, node := range nodes {
+		sortTree(node.children)
+	}
+}
+
+func printTreeNode(w io.Writer, node *treeNode, prefix, childPrefix string) {
+	fmt.Fprintf(w, "%-70s %s\n", prefix+node.kind+"/"+node.meta.GetName(), node.status)
+	for i, child := range node.children {
+		if i == len(node.children)-1 {
+			printTreeNode(w, child, childPrefix+"└─ ", childPrefix+"   ")
+		} else {
+			printTreeNode(w, child, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+var usedByColumns = []column[UsedByRow]{
+	{"POD", 40, func(r UsedByRow) string { return r.Pod }, nil},
+	{"CONTAINER", 25, func(r UsedByRow) string { return orNone(r.Container) }, nil},
+	{"VIA", 17, func(r UsedByRow) string { return r.Via }, nil},
+	{"UPDATES", 11, func(r UsedByRow) string { return r.Updates }, nil},
+	{"DETAIL", 0, func(r UsedByRow) string { return r.Detail }, nil},
+}
+
+// printUsedBy answers, for -used-by, what a change to a ConfigMap or
+// Secret would reach: every pod in the namespace that mounts it or reads
+// it into its environment, and whether the pod sees the change live or
+// only once its containers restart. kind is "configmaps" or "secrets".
+func printUsedBy(ctx context.Context, p *printer, src *source, kind, namespace, name string) {
+	opts := metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()}
+	var found bool
+	if kind == "configmaps" {
+		configMaps, err := fetch(src, &corev1.ConfigMapList{}, namespace, opts, func() (*corev1.ConfigMapList, error) {
+			return src.clientset.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			handleError(err)
+			return
+		}
+		found = len(configMaps.Items) > 0
+	} else {
+		secrets, err := fetch(src, &corev1.SecretList{}, namespace, opts, func() (*corev1.SecretList, error) {
+			return src.clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			handleError(err)
+			return
+		}
+		found = len(secrets.Items) > 0
+	}
+
+	pods, err := fetch(src, &corev1.PodList{}, namespace, metav1.ListOptions{}, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []UsedByRow
+	users := 0
+	for _, pod := range pods.Items {
+		podRows := buildUsedByRows(pod, kind == "secrets", name)
+		if len(podRows) > 0 {
+			users++
+		}
+		rows = append(rows, podRows...)
+	}
+
+	if p.format == "table" {
+		object := "ConfigMap"
+		if kind == "secrets" {
+			object = "Secret"
+		}
+		fmt.Fprintf(p.w, "\n%s %s/%s is used by %d pod(s)\n", object, namespace, name, users)
+		if !found {
+			// pods that need it do not start until it exists
+			fmt.Fprintf(p.w, "Warning: %s %s/%s does not exist\n", object, namespace, name)
+		}
+	}
+	printRows(p, "references", nil, usedByColumns, rows)
+}
+
+// buildUsedByRows lists the pod's references to the ConfigMap, or Secret
+// when secret is set, called name: volumes, per container mounting them,
+// envFrom and valueFrom, and for a Secret imagePullSecrets. Mounted
+// volumes are updated in place by the kubelet except through subPath;
+// environment variables are only read when a container starts.
+func buildUsedByRows(pod corev1.Pod, secret bool, name string) []UsedByRow {
+	row := func(container, via, updates, detail string) UsedByRow {
+		return UsedByRow{Namespace: pod.Namespace, Pod: pod.Name, Container: container, Via: via, Updates: updates, Detail: detail}
+	}
+	containers := append(pod.Spec.InitContainers[:len(pod.Spec.InitContainers):len(pod.Spec.InitContainers)], pod.Spec.Containers...)
+
+	var rows []UsedByRow
+	for _, volume := range pod.Spec.Volumes {
+		via := ""
+		switch {
+		case !secret && volume.ConfigMap != nil && volume.ConfigMap.Name == name,
+			secret && volume.Secret != nil && volume.Secret.SecretName == name:
+			via = "volume"
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if (!secret && source.ConfigMap != nil && source.ConfigMap.Name == name) ||
+					(secret && source.Secret != nil && source.Secret.Name == name) {
+					via = "projected volume"
+				}
+			}
+		}
+		if via == "" {
+			continue
+		}
+
+		mounted := false
+		for _, c := range containers {
+			for _, mount := range c.VolumeMounts {
+				if mount.Name != volume.Name {
+					continue
+				}
+				mounted = true
+				updates, detail := "live", fmt.Sprintf("volume %s at %s", volume.Name, mount.MountPath)
+				if mount.SubPath != "" || mount.SubPathExpr != "" {
+					updates, detail = "on restart", detail+" (subPath)"
+				}
+				rows = append(rows, row(c.Name, via, updates, detail))
+			}
+		}
+		if !mounted {
+			rows = append(rows, row("", via, "-", "volume "+volume.Name+", not mounted"))
+		}
+	}
+
+	for _, c := range containers {
+		for _, envFrom := range c.EnvFrom {
+			if (!secret && envFrom.ConfigMapRef != nil && envFrom.ConfigMapRef.Name == name) ||
+				(secret && envFrom.SecretRef != nil && envFrom.SecretRef.Name == name) {
+				rows = append(rows, row(c.Name, "envFrom", "on restart", "every key, prefix "+orNone(envFrom.Prefix)))
+			}
+		}
+		for _, env := range c.Env {
+			from := env.ValueFrom
+			if from == nil {
+				continue
+			}
+			if !secret && from.ConfigMapKeyRef != nil && from.ConfigMapKeyRef.Name == name {
+				rows = append(rows, row(c.Name, "env", "on restart", env.Name+" from key "+from.ConfigMapKeyRef.Key))
+			}
+			if secret && from.SecretKeyRef != nil && from.SecretKeyRef.Name == name {
+				rows = append(rows, row(c.Name, "env", "on restart", env.Name+" from key "+from.SecretKeyRef.Key))
+			}
+		}
+	}
+
+	if secret {
+		for _, ref := range pod.Spec.ImagePullSecrets {
+			if ref.Name == name {
+				// read when an image is next pulled
+				rows = append(rows, row("", "imagePullSecrets", "next pull", "registry credentials"))
+			}
+		}
+	}
+	return rows
+}
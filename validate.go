@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// validateServices looks for service configuration conflicts that the API
+// server accepts but that break traffic in confusing ways: a nodePort or
+// clusterIP used twice anywhere in the cluster, and services in namespace
+// whose selectors pick the same pods.
+func validateServices(ctx context.Context, src *source, namespace string) ([]string, error) {
+	all, err := fetch(src, &corev1.ServiceList{}, "", metav1.ListOptions{}, func() (*corev1.ServiceList, error) {
+		return src.clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	nodePorts := map[string][]string{}
+	clusterIPs := map[string][]string{}
+	for _, svc := range all.Items {
+		id := svc.Namespace + "/" + svc.Name
+		for _, port := range svc.Spec.Ports {
+			if port.NodePort != 0 {
+				key := fmt.Sprintf("%d/%s", port.NodePort, port.Protocol)
+				nodePorts[key] = appendUnique(nodePorts[key], id)
+			}
+		}
+		if ip := svc.Spec.ClusterIP; ip != "" && ip != corev1.ClusterIPNone {
+			clusterIPs[ip] = appendUnique(clusterIPs[ip], id)
+		}
+	}
+	for port, services := range nodePorts {
+		if len(services) > 1 {
+			warnings = append(warnings, fmt.Sprintf("nodePort %s is used by %s", port, strings.Join(services, ", ")))
+		}
+	}
+	for ip, services := range clusterIPs {
+		if len(services) > 1 {
+			warnings = append(warnings, fmt.Sprintf("clusterIP %s is used by %s", ip, strings.Join(services, ", ")))
+		}
+	}
+
+	pods, err := fetch(src, &corev1.PodList{}, namespace, metav1.ListOptions{}, func() (*corev1.PodList, error) {
+		return src.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	selected := map[string][]string{} // service name -> pod names
+	var names []string
+	for _, svc := range all.Items {
+		if svc.Namespace != namespace || len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		for _, pod := range pods.Items {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				selected[svc.Name] = append(selected[svc.Name], pod.Name)
+			}
+		}
+		names = append(names, svc.Name)
+	}
+	sort.Strings(names)
+	for i, a := range names {
+		for _, b := range names[i+1:] {
+			if shared := intersect(selected[a], selected[b]); len(shared) > 0 {
+				warnings = append(warnings, fmt.Sprintf("services %s and %s both select pods %s", a, b, strings.Join(shared, ", ")))
+			}
+		}
+	}
+
+	sort.Strings(warnings)
+	return warnings, nil
+}
+
+func appendUnique(items []string, item string) []string {
+	for _, existing := range items {
+		if existing == item {
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+func intersect(a, b []string) []string {
+	in := map[string]bool{}
+	for _, item := range a {
+		in[item] = true
+	}
+	var shared []string
+	for _, item := range b {
+		if in[item] {
+			shared = append(shared, item)
+		}
+	}
+	return shared
+}
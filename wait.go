@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Exit codes for the CI-oriented modes.
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitNotReady = 2
+)
+
+// timeLimit is why a context with a deadline ended: the flag that set
+// the limit, and its value.
+type timeLimit struct {
+	flag  string
+	limit time.Duration
+}
+
+func (t timeLimit) Error() string {
+	return fmt.Sprintf("%s of %s reached", t.flag, t.limit)
+}
+
+// waitServicesReady polls until every selected service has at least one
+// ready endpoint, or timeout (0 for no limit) elapses. It returns the
+// process exit code.
+func waitServicesReady(ctx context.Context, w io.Writer, src *source, namespace string, opts metav1.ListOptions, interval, timeout time.Duration) int {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, timeLimit{"-timeout", timeout})
+		defer cancel()
+	}
+
+	var notReady []string
+	for {
+		pending, total, err := servicesWithoutEndpoints(ctx, src, namespace, opts)
+		if err != nil {
+			// an error caused by the deadline itself is reported as a timeout
+			if ctx.Err() == nil {
+				handleError(err)
+				return exitError
+			}
+		} else {
+			notReady = pending
+			if len(notReady) == 0 {
+				fmt.Fprintf(w, "All %d services in namespace %s have ready endpoints\n", total, namespace)
+				return exitOK
+			}
+			fmt.Fprintf(w, "%s waiting for %d/%d services: %s\n", time.Now().Format("15:04:05"), len(notReady), total, strings.Join(notReady, ", "))
+		}
+
+		select {
+		case <-ctx.Done():
+			// -max-duration may end the wait before -timeout, or with none
+			var limit timeLimit
+			if errors.As(context.Cause(ctx), &limit) {
+				fmt.Fprintf(w, "Timed out after %s (%s) waiting for services: %s\n", limit.limit, limit.flag, strings.Join(notReady, ", "))
+			} else {
+				fmt.Fprintf(w, "Stopped waiting for services: %s\n", strings.Join(notReady, ", "))
+			}
+			return exitNotReady
+		case <-time.After(interval):
+		}
+	}
+}
+
+// servicesWithoutEndpoints returns the selected services that have no ready
+// endpoint, and how many services were checked. ExternalName services have
+// no endpoints and are skipped.
+func servicesWithoutEndpoints(ctx context.Context, src *source, namespace string, opts metav1.ListOptions) ([]string, int, error) {
+	services, err := fetch(src, &corev1.ServiceList{}, namespace, opts, func() (*corev1.ServiceList, error) {
+		return src.clientset.CoreV1().Services(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	slices, err := fetch(src, &discoveryv1.EndpointSliceList{}, namespace, metav1.ListOptions{}, func() (*discoveryv1.EndpointSliceList, error) {
+		return src.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ready := map[string]int{}
+	for _, slice := range slices.Items {
+		ready[slice.Labels[discoveryv1.LabelServiceName]] += countReadyEndpoints(slice)
+	}
+
+	var notReady []string
+	total := 0
+	for _, svc := range services.Items {
+		if svc.Spec.Type == corev1.ServiceTypeExternalName {
+			continue
+		}
+		total++
+		if ready[svc.Name] == 0 {
+			notReady = append(notReady, svc.Name)
+		}
+	}
+	sort.Strings(notReady)
+	return notReady, total, nil
+}
+
+// countReadyEndpoints counts the ready endpoints in a slice. A nil Ready
+// condition means ready, per the EndpointSlice API.
+func countReadyEndpoints(slice discoveryv1.EndpointSlice) int {
+	count := 0
+	for _, endpoint := range slice.Endpoints {
+		if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+			count++
+		}
+	}
+	return count
+}
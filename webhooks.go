@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var webhookConfigColumns = []column[WebhookConfigRow]{
+	{"NAME", 50, func(r WebhookConfigRow) string { return r.Name }, nil},
+	{"WEBHOOKS", 9, func(r WebhookConfigRow) string { return strconv.Itoa(r.Webhooks) }, nil},
+	{"FAILURE-POLICY", 15, func(r WebhookConfigRow) string { return joinOrNone(r.FailurePolicies) }, nil},
+	{"AGE", 10, func(r WebhookConfigRow) string { return r.Age }, ignored[WebhookConfigRow]},
+	{"SERVICE", 0, func(r WebhookConfigRow) string { return joinOrNone(r.Targets) }, nil},
+}
+
+func listValidatingWebhooks(ctx context.Context, p *printer, src *source, opts metav1.ListOptions) {
+	configs, err := fetch(src, &admissionregistrationv1.ValidatingWebhookConfigurationList{}, "", opts, func() (*admissionregistrationv1.ValidatingWebhookConfigurationList, error) {
+		return src.clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []WebhookConfigRow
+	for _, config := range configs.Items {
+		row := newWebhookConfigRow(config.ObjectMeta)
+		for _, webhook := range config.Webhooks {
+			row.addWebhook(webhook.ClientConfig, webhook.FailurePolicy)
+		}
+		rows = append(rows, row)
+	}
+	printRows(p, "validatingwebhookconfigurations", configs, webhookConfigColumns, rows)
+}
+
+func listMutatingWebhooks(ctx context.Context, p *printer, src *source, opts metav1.ListOptions) {
+	configs, err := fetch(src, &admissionregistrationv1.MutatingWebhookConfigurationList{}, "", opts, func() (*admissionregistrationv1.MutatingWebhookConfigurationList, error) {
+		return src.clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, opts)
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var rows []WebhookConfigRow
+	for _, config := range configs.Items {
+		row := newWebhookConfigRow(config.ObjectMeta)
+		for _, webhook := range config.Webhooks {
+			row.addWebhook(webhook.ClientConfig, webhook.FailurePolicy)
+		}
+		rows = append(rows, row)
+	}
+	printRows(p, "mutatingwebhookconfigurations", configs, webhookConfigColumns, rows)
+}
+
+// addWebhook counts one webhook of the configuration, noting where it
+// sends requests and what happens when that fails. An unset failure
+// policy defaults to Fail.
+func (r *WebhookConfigRow) addWebhook(client admissionregistrationv1.WebhookClientConfig, policy *admissionregistrationv1.FailurePolicyType) {
+	r.Webhooks++
+	failurePolicy := string(admissionregistrationv1.Fail)
+	if policy != nil {
+		failurePolicy = string(*policy)
+	}
+	r.FailurePolicies = appendUnique(r.FailurePolicies, failurePolicy)
+
+	target := "<none>"
+	switch {
+	case client.Service != nil:
+		port := int32(443)
+		if client.Service.Port != nil {
+			port = *client.Service.Port
+		}
+		target = fmt.Sprintf("%s/%s:%d", client.Service.Namespace, client.Service.Name, port)
+	case client.URL != nil:
+		target = *client.URL
+	}
+	r.Targets = appendUnique(r.Targets, target)
+}
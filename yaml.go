@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// printObjectYAML prints the one object of resourceType called name as
+// YAML, like kubectl get -o yaml. managedFields are stripped unless
+// showManagedFields is set, and status too with stripStatus.
+func printObjectYAML(ctx context.Context, w io.Writer, src *source, resourceType, namespace, name string, showManagedFields, stripStatus bool) error {
+	obj, err := getObject(ctx, src, resourceType, namespace, name)
+	if err != nil {
+		return err
+	}
+	content, err := toUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	object := &unstructured.Unstructured{Object: runtime.DeepCopyJSON(content)}
+	if object.GetKind() == "" {
+		// typed objects from a dump lost apiVersion and kind on decoding
+		if kinds, _, err := scheme.Scheme.ObjectKinds(obj); err == nil {
+			object.SetGroupVersionKind(kinds[0])
+		}
+	}
+	if !showManagedFields {
+		object.SetManagedFields(nil)
+	}
+	if stripStatus {
+		unstructured.RemoveNestedField(object.Object, "status")
+	}
+
+	data, err := yaml.Marshal(object.Object)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// getObject fetches one object of any resource the server knows about
+// through the dynamic client, or finds it in the dump.
+func getObject(ctx context.Context, src *source, resourceType, namespace, name string) (runtime.Object, error) {
+	resource := schema.ParseGroupResource(resourceType)
+	if info, ok := lookupResource(resourceType); ok {
+		resource = info.gvr.GroupResource()
+		if !info.namespaced {
+			namespace = ""
+		}
+	}
+
+	if src.dump != nil {
+		for _, obj := range src.dump {
+			kinds, _, err := scheme.Scheme.ObjectKinds(obj)
+			if err != nil {
+				continue
+			}
+			plural, _ := meta.UnsafeGuessKindToResource(kinds[0])
+			accessor, err := meta.Accessor(obj)
+			if err != nil || plural.GroupResource() != resource || accessor.GetName() != name {
+				continue
+			}
+			if namespace == "" || accessor.GetNamespace() == "" || accessor.GetNamespace() == namespace {
+				return obj, nil
+			}
+		}
+		return nil, apierrors.NewNotFound(resource, name)
+	}
+
+	gvr, namespaced, err := src.resolveResource(resource.String())
+	if err != nil {
+		return nil, err
+	}
+	if namespaced {
+		return src.dynamic.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	return src.dynamic.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+}
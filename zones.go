@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodeZone is the node's failure domain, from the well-known label or its
+// deprecated beta name.
+func nodeZone(node corev1.Node) string {
+	if zone, ok := node.Labels[corev1.LabelTopologyZone]; ok {
+		return zone
+	}
+	return node.Labels[corev1.LabelFailureDomainBetaZone]
+}
+
+// nodeZones maps node names to zones, so pods can show the zone they run
+// in. Listing nodes needs cluster-wide access; without it pods show no
+// zone rather than failing the whole listing.
+func nodeZones(ctx context.Context, src *source) map[string]string {
+	nodes, err := fetch(src, &corev1.NodeList{}, "", metav1.ListOptions{}, func() (*corev1.NodeList, error) {
+		return src.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot list nodes for pod zones: %v\n", err)
+		return nil
+	}
+
+	zones := map[string]string{}
+	for _, node := range nodes.Items {
+		zones[node.Name] = nodeZone(node)
+	}
+	return zones
+}